@@ -0,0 +1,185 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+	log "github.com/sirupsen/logrus"
+)
+
+// PriceProvider returns spot prices. *coinbase.CoinbaseClient implements it.
+type PriceProvider interface {
+	RetrieveSpotPrice(crypto coinbase.CryptoSymbol, fiat coinbase.FiatSymbol) float64
+}
+
+type Config struct {
+	Rules []Rule
+	// Prices defaults to coinbase.GetPriceClient().
+	Prices PriceProvider
+	// Assets looks up metadata for rules with metadata conditions. Orders
+	// don't include metadata, so without it those rules never match orders
+	// passed to Evaluate.
+	Assets assets.Client
+}
+
+type Engine struct {
+	rules  []Rule
+	prices PriceProvider
+	assets assets.Client
+}
+
+// Match is an order meeting every condition of a rule.
+type Match struct {
+	Rule      *Rule
+	Order     api.Order
+	Price     float64
+	Currency  string
+	FiatPrice float64
+}
+
+func NewEngine(cfg Config) *Engine {
+	e := &Engine{rules: cfg.Rules, prices: cfg.Prices, assets: cfg.Assets}
+	if e.prices == nil {
+		e.prices = coinbase.GetPriceClient()
+	}
+
+	return e
+}
+
+// Evaluate returns the matches of every rule against the given orders.
+func (e *Engine) Evaluate(ctx context.Context, list []api.Order) ([]Match, error) {
+	metadata := make(map[string]map[string]interface{})
+
+	var matches []Match
+	for i := range e.rules {
+		for _, order := range list {
+			m, err := e.evaluate(ctx, &e.rules[i], order, metadata)
+			if err != nil {
+				return matches, err
+			}
+
+			if m != nil {
+				matches = append(matches, *m)
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// Check fetches the candidate orders of each rule with client and returns the
+// matches.
+func (e *Engine) Check(ctx context.Context, client orders.Client) ([]Match, error) {
+	var matches []Match
+	for i := range e.rules {
+		r := &e.rules[i]
+		cfg, err := r.ListOrdersConfig()
+		if err != nil {
+			return matches, err
+		}
+
+		list, err := client.ListOrders(ctx, cfg)
+		if err != nil {
+			return matches, fmt.Errorf("could not fetch orders for rule %s: %w", r.Name, err)
+		}
+
+		log.Debugf("rule %s: evaluating %d orders", r.Name, len(list))
+		for _, order := range list {
+			// The query already filtered on metadata.
+			m, err := e.evaluate(ctx, r, order, nil)
+			if err != nil {
+				return matches, err
+			}
+
+			if m != nil {
+				matches = append(matches, *m)
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// evaluate checks an order against a rule. Metadata is looked up and cached in
+// metadata, or skipped when metadata is nil.
+func (e *Engine) evaluate(ctx context.Context, r *Rule, order api.Order, metadata map[string]map[string]interface{}) (*Match, error) {
+	sell, buy := order.GetSell().Data, order.GetBuy().Data
+	switch {
+	case r.Status != "" && order.Status != r.Status:
+		return nil, nil
+	case r.address != "" && !strings.EqualFold(sell.GetTokenAddress(), r.address):
+		return nil, nil
+	case r.seller != "" && !strings.EqualFold(order.User, r.seller):
+		return nil, nil
+	case r.currencyAddress != "" && !strings.EqualFold(buy.GetTokenAddress(), r.currencyAddress):
+		return nil, nil
+	}
+
+	currency := string(orders.CurrencySymbol(order))
+	if r.Currency != "" && currency != r.Currency {
+		return nil, nil
+	}
+
+	price, ok := orders.OrderPrice(order, !r.ExcludeFees)
+	if !ok {
+		return nil, nil
+	}
+
+	if (r.MinPrice > 0 && price < r.MinPrice) || (r.MaxPrice > 0 && price > r.MaxPrice) {
+		return nil, nil
+	}
+
+	m := &Match{Rule: r, Order: order, Price: price, Currency: currency}
+	if r.Fiat != "" {
+		spot := e.prices.RetrieveSpotPrice(coinbase.CryptoSymbol(currency), r.Fiat)
+		if spot <= 0 {
+			log.Debugf("rule %s: no %s/%s spot price, skipping order %d", r.Name, currency, r.Fiat, order.OrderId)
+			return nil, nil
+		}
+
+		m.FiatPrice = price * spot
+		if (r.MinFiat > 0 && m.FiatPrice < r.MinFiat) || (r.MaxFiat > 0 && m.FiatPrice > r.MaxFiat) {
+			return nil, nil
+		}
+	}
+
+	if len(r.Metadata) > 0 && metadata != nil {
+		ok, err := e.matchMetadata(ctx, r, sell, metadata)
+		if err != nil || !ok {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+func (e *Engine) matchMetadata(ctx context.Context, r *Rule, sell api.OrderData, cache map[string]map[string]interface{}) (bool, error) {
+	if e.assets == nil || sell.GetTokenId() == "" {
+		return false, nil
+	}
+
+	key := strings.ToLower(sell.GetTokenAddress()) + "/" + sell.GetTokenId()
+	metadata, ok := cache[key]
+	if !ok {
+		asset, err := e.assets.GetAsset(ctx, sell.GetTokenAddress(), sell.GetTokenId(), false)
+		if err != nil {
+			return false, fmt.Errorf("could not fetch metadata of %s: %w", key, err)
+		}
+
+		metadata = asset.Metadata
+		cache[key] = metadata
+	}
+
+	for k, v := range r.Metadata {
+		if fmt.Sprint(metadata[k]) != v {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,260 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/imxtest"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+const (
+	heroAddress   = "0x6465ef3009f3c474774f4afb607a5d600ea71d95"
+	portalAddress = "0xe4ac52f4b4a721d1d0ad8c9c689df401c2db7291"
+)
+
+// usdcOrder is a hero listed for 40 USDC.
+const usdcOrder = `{
+	"order_id": 10,
+	"status": "active",
+	"user": "0x2222222222222222222222222222222222222222",
+	"sell": {"type": "ERC721", "data": {"token_address": "0x6465ef3009f3c474774f4afb607a5d600ea71d95", "token_id": "4", "quantity": "1"}},
+	"buy": {"type": "ERC20", "data": {"token_address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "decimals": 6, "symbol": "USDC", "quantity": "40000000"}}
+}`
+
+func parseRules(t *testing.T, content string) []Rule {
+	t.Helper()
+
+	rules, err := Parse([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rules
+}
+
+func matchedIDs(matches []Match) []int32 {
+	var ids []int32
+	for _, m := range matches {
+		ids = append(ids, m.Order.OrderId)
+	}
+
+	return ids
+}
+
+func equalIDs(a, b []int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestParseValidation(t *testing.T) {
+	invalid := map[string]string{
+		"unknown key":       "rules:\n  - name: a\n    max_prise: 1\n",
+		"missing name":      "rules:\n  - collection: hero\n",
+		"duplicate name":    "rules:\n  - name: a\n  - name: a\n",
+		"negative price":    "rules:\n  - name: a\n    min_price: -1\n",
+		"min above max":     "rules:\n  - name: a\n    min_price: 2\n    max_price: 1\n",
+		"fiat min over max": "rules:\n  - name: a\n    min_fiat: 2\n    max_fiat: 1\n",
+		"bad fiat":          "rules:\n  - name: a\n    fiat: dollars\n",
+		"bad address":       "rules:\n  - name: a\n    currency: XYZ\n    currency_address: 0x123\n",
+		"eth address":       "rules:\n  - name: a\n    currency: eth\n    currency_address: " + heroAddress + "\n",
+	}
+
+	for name, content := range invalid {
+		if _, err := Parse([]byte(content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	rules := parseRules(t, "rules:\n  - name: a\n    collection: hero\n    currency: eth\n    max_fiat: 10\n")
+	r := rules[0]
+	if r.Fiat != coinbase.FiatUSD || r.Status != "active" || r.Limit != DefaultLimit || r.Currency != "ETH" || r.address != heroAddress {
+		t.Errorf("defaults not applied: %+v", r)
+	}
+
+	if rules := parseRules(t, ""); len(rules) != 0 {
+		t.Errorf("got %d rules from an empty file", len(rules))
+	}
+}
+
+func TestListOrdersConfig(t *testing.T) {
+	rules := parseRules(t, `
+rules:
+  - name: eth
+    collection: hero
+    currency: eth
+  - name: usdc
+    currency: usdc
+  - name: custom
+    currency: xyz
+    currency_address: 0xABCDEF0000000000000000000000000000000001
+  - name: unknown
+    currency: xyz
+  - name: any
+    metadata:
+      rarity: Legendary
+`)
+
+	tests := []struct {
+		tokenType, tokenAddress string
+	}{
+		{"ETH", ""},
+		{"ERC20", ERC20Addresses["USDC"]},
+		{"ERC20", "0xabcdef0000000000000000000000000000000001"},
+		{"ERC20", ""},
+		{"", ""},
+	}
+
+	for i, tt := range tests {
+		cfg, err := rules[i].ListOrdersConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if cfg.BuyTokenType != tt.tokenType || cfg.BuyTokenAddress != tt.tokenAddress {
+			t.Errorf("%s: got buy token %q %q, want %q %q", rules[i].Name, cfg.BuyTokenType, cfg.BuyTokenAddress, tt.tokenType, tt.tokenAddress)
+		}
+	}
+
+	cfg, _ := rules[0].ListOrdersConfig()
+	if cfg.SellTokenAddress != heroAddress || cfg.Status != "active" || cfg.PageSize != DefaultLimit {
+		t.Errorf("unexpected query %+v", cfg)
+	}
+
+	cfg, _ = rules[4].ListOrdersConfig()
+	if cfg.SellMetadata != `{"rarity":["Legendary"]}` {
+		t.Errorf("got metadata filter %s", cfg.SellMetadata)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	ctx := context.Background()
+	f := imxtest.DefaultFixtures()
+
+	var usdc api.Order
+	if err := json.Unmarshal([]byte(usdcOrder), &usdc); err != nil {
+		t.Fatal(err)
+	}
+	list := append(f.Orders, usdc)
+
+	prices := imxtest.NewPriceClient(map[coinbase.SpotPair]float64{
+		{Crypto: "ETH", Fiat: coinbase.FiatUSD}: 1000,
+	})
+
+	tests := []struct {
+		rule string
+		want []int32
+	}{
+		{"name: all", []int32{1, 2, 5, 10}},
+		{"name: filled\nstatus: filled", []int32{3}},
+		{"name: heroes\ncollection: hero", []int32{1, 2, 10}},
+		{"name: cheap eth\ncurrency: ETH\nmax_price: 1", []int32{1, 2}},
+		{"name: range\nmin_price: 0.75\nmax_price: 2", []int32{1}},
+		{"name: seller\nseller: \"0x2222222222222222222222222222222222222222\"", []int32{2, 5, 10}},
+		{"name: usdc\ncurrency: USDC\nmax_price: 50", []int32{10}},
+		{"name: usdc too low\ncurrency: USDC\nmax_price: 30", nil},
+		{"name: fiat\ncurrency: ETH\nmin_fiat: 600\nmax_fiat: 2000", []int32{1}},
+		// There's no USDC spot price, so fiat rules skip USDC orders.
+		{"name: fiat usdc\ncurrency: USDC\nmax_fiat: 100", nil},
+	}
+
+	for _, tt := range tests {
+		rules := parseRules(t, "rules:\n  - "+strings.ReplaceAll(tt.rule, "\n", "\n    "))
+		e := NewEngine(Config{Rules: rules, Prices: prices})
+
+		matches, err := e.Evaluate(ctx, list)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := matchedIDs(matches); !equalIDs(got, tt.want) {
+			t.Errorf("%s: matched %v, want %v", rules[0].Name, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateMatchPrices(t *testing.T) {
+	f := imxtest.DefaultFixtures()
+	prices := imxtest.NewPriceClient(map[coinbase.SpotPair]float64{
+		{Crypto: "ETH", Fiat: coinbase.FiatEUR}: 2000,
+	})
+
+	rules := parseRules(t, "rules:\n  - name: a\n    collection: portal\n    fiat: eur\n")
+	e := NewEngine(Config{Rules: rules, Prices: prices})
+
+	matches, err := e.Evaluate(context.Background(), f.Orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 1 {
+		t.Fatalf("got %d matches, want 1", len(matches))
+	}
+
+	m := matches[0]
+	if m.Price != 3 || m.Currency != "ETH" || m.FiatPrice != 6000 || m.Rule.Name != "a" {
+		t.Errorf("unexpected match %+v", m)
+	}
+}
+
+func TestEvaluateMetadata(t *testing.T) {
+	ctx := context.Background()
+	f := imxtest.DefaultFixtures()
+	rules := parseRules(t, "rules:\n  - name: epic\n    metadata:\n      Rarity: Epic\n  - name: any epic\n    metadata:\n      Rarity: Epic\n")
+
+	matches, err := NewEngine(Config{Rules: rules}).Evaluate(ctx, f.Orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 0 {
+		t.Errorf("metadata rules matched %d orders without an assets client", len(matches))
+	}
+
+	client := imxtest.NewAssetsClient(f)
+	matches, err = NewEngine(Config{Rules: rules, Assets: client}).Evaluate(ctx, f.Orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := matchedIDs(matches); !equalIDs(got, []int32{2, 5, 2, 5}) {
+		t.Errorf("matched %v, want [2 5 2 5]", got)
+	}
+
+	// Metadata is fetched once per asset across rules.
+	if n := len(client.CallsTo("GetAsset")); n != 3 {
+		t.Errorf("GetAsset called %d times, want 3", n)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	f := imxtest.DefaultFixtures()
+	client := imxtest.NewOrdersClient(f)
+	rules := parseRules(t, "rules:\n  - name: heroes\n    collection: hero\n    max_price: 0.75\n  - name: portals\n    collection: portal\n")
+
+	matches, err := NewEngine(Config{Rules: rules}).Check(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := matchedIDs(matches); !equalIDs(got, []int32{2, 5}) {
+		t.Errorf("matched %v, want [2 5]", got)
+	}
+
+	calls := client.CallsTo("ListOrders")
+	if len(calls) != 2 {
+		t.Fatalf("ListOrders called %d times, want 2", len(calls))
+	}
+}
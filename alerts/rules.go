@@ -0,0 +1,223 @@
+// Package alerts evaluates price alert rules over orders, e.g. "any Legendary
+// hero listed under 0.05 ETH".
+//
+// Rules are loaded from a YAML (or JSON) file:
+//
+//	rules:
+//	  - name: cheap legendary heroes
+//	    collection: hero
+//	    metadata:
+//	      rarity: Legendary
+//	    currency: ETH
+//	    max_price: 0.05
+//	  - name: portals under $20
+//	    collection: portal
+//	    max_fiat: 20
+//	    fiat: USD
+//	  - name: heroes for USDC
+//	    collection: hero
+//	    currency: USDC
+//	    max_price: 50
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"gopkg.in/yaml.v3"
+)
+
+const DefaultLimit = 100
+
+// ERC20Addresses are the token addresses of ERC20 currencies by symbol, used
+// to fetch only the orders priced in a rule's currency. Rules in other ERC20
+// currencies can set currency_address.
+var ERC20Addresses = map[string]string{
+	"GODS": "0xccc8cb5229b0ac8069c51fd58367fd1e622afd97",
+	"IMX":  "0xf57e7e7c23978c3caec3c3548e3d615c346e79ff",
+	"USDC": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+}
+
+// Rule is a set of conditions an order must all meet to match. Zero values
+// don't restrict matches.
+type Rule struct {
+	Name string `yaml:"name" json:"name"`
+	// Collection is the address or shortcut of the collection being sold.
+	Collection string `yaml:"collection" json:"collection"`
+	// Metadata matches the sold asset's metadata values.
+	Metadata map[string]string `yaml:"metadata" json:"metadata"`
+	// Currency is the symbol the order is priced in, e.g. ETH.
+	Currency string `yaml:"currency" json:"currency"`
+	// CurrencyAddress is the token address of an ERC20 currency, looked up
+	// in ERC20Addresses when empty.
+	CurrencyAddress string  `yaml:"currency_address" json:"currency_address"`
+	MinPrice        float64 `yaml:"min_price" json:"min_price"`
+	MaxPrice        float64 `yaml:"max_price" json:"max_price"`
+	// ExcludeFees compares prices without fees. Prices include fees by
+	// default, as that's what a buyer pays.
+	ExcludeFees bool `yaml:"exclude_fees" json:"exclude_fees"`
+	// Fiat thresholds convert the price with the current spot price.
+	Fiat    coinbase.FiatSymbol `yaml:"fiat" json:"fiat"`
+	MinFiat float64             `yaml:"min_fiat" json:"min_fiat"`
+	MaxFiat float64             `yaml:"max_fiat" json:"max_fiat"`
+	// Seller is an address or wallet alias.
+	Seller string `yaml:"seller" json:"seller"`
+	// Status defaults to active.
+	Status string `yaml:"status" json:"status"`
+	// Limit caps how many orders Engine.Check fetches for the rule, cheapest
+	// first. Defaults to DefaultLimit.
+	Limit int `yaml:"limit" json:"limit"`
+
+	address         string
+	seller          string
+	currencyAddress string
+}
+
+type ruleFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// LoadFile parses a rule file.
+func LoadFile(path string) ([]Rule, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read rules %s: %w", path, err)
+	}
+
+	rules, err := Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules %s: %w", path, err)
+	}
+
+	return rules, nil
+}
+
+// Parse parses and validates rules. Unknown keys are errors, so typos don't
+// silently widen a rule.
+func Parse(content []byte) ([]Rule, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+
+	var file ruleFile
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(file.Rules))
+	for i := range file.Rules {
+		r := &file.Rules[i]
+		if err := r.init(); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+
+		if names[r.Name] {
+			return nil, fmt.Errorf("rule %d: duplicate name %q", i+1, r.Name)
+		}
+		names[r.Name] = true
+	}
+
+	return file.Rules, nil
+}
+
+func (r *Rule) init() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+
+	switch {
+	case r.MinPrice < 0 || r.MaxPrice < 0 || r.MinFiat < 0 || r.MaxFiat < 0:
+		return fmt.Errorf("%s: prices can't be negative", r.Name)
+	case r.MaxPrice > 0 && r.MinPrice > r.MaxPrice:
+		return fmt.Errorf("%s: min_price is above max_price", r.Name)
+	case r.MaxFiat > 0 && r.MinFiat > r.MaxFiat:
+		return fmt.Errorf("%s: min_fiat is above max_fiat", r.Name)
+	}
+
+	if r.MinFiat > 0 || r.MaxFiat > 0 || r.Fiat != "" {
+		if r.Fiat == "" {
+			r.Fiat = coinbase.FiatUSD
+		}
+
+		fiat, err := coinbase.NewFiatSymbol(string(r.Fiat))
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Name, err)
+		}
+		r.Fiat = fiat
+	}
+
+	if r.Status == "" {
+		r.Status = "active"
+	}
+
+	if r.Limit <= 0 {
+		r.Limit = DefaultLimit
+	}
+
+	r.Currency = strings.ToUpper(r.Currency)
+	switch {
+	case r.CurrencyAddress != "" && r.Currency == "ETH":
+		return fmt.Errorf("%s: currency_address can't be set for ETH", r.Name)
+	case r.CurrencyAddress != "":
+		if err := collections.ValidateAddress(r.CurrencyAddress); err != nil {
+			return fmt.Errorf("%s: %w", r.Name, err)
+		}
+		r.currencyAddress = strings.ToLower(r.CurrencyAddress)
+	default:
+		r.currencyAddress = ERC20Addresses[r.Currency]
+	}
+
+	r.address = strings.ToLower(collections.ResolveCollection("collection", r.Collection))
+	r.seller = collections.ResolveWallet("seller", r.Seller)
+
+	return nil
+}
+
+// ListOrdersConfig returns a query for the orders that may match the rule,
+// cheapest first. Price conditions are left to evaluation, since the API
+// filters on quantities in the currency's smallest unit.
+func (r *Rule) ListOrdersConfig() (*orders.ListOrdersConfig, error) {
+	cfg := &orders.ListOrdersConfig{
+		Direction:        "asc",
+		IncludeFees:      true,
+		OrderBy:          "buy_quantity",
+		PageSize:         r.Limit,
+		SellTokenAddress: r.address,
+		Status:           r.Status,
+		User:             r.seller,
+	}
+
+	switch {
+	case r.Currency == "ETH":
+		cfg.BuyTokenType = "ETH"
+	case r.currencyAddress != "":
+		cfg.BuyTokenType = "ERC20"
+		cfg.BuyTokenAddress = r.currencyAddress
+	case r.Currency != "":
+		// Unknown ERC20 currencies are filtered on their symbol when the
+		// orders are evaluated.
+		cfg.BuyTokenType = "ERC20"
+	}
+
+	if len(r.Metadata) > 0 {
+		filter := make(map[string][]string, len(r.Metadata))
+		for k, v := range r.Metadata {
+			filter[k] = []string{v}
+		}
+
+		data, err := json.Marshal(filter)
+		if err != nil {
+			return nil, err
+		}
+		cfg.SellMetadata = string(data)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,118 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/deadloct/immutablex-go-lib/collections"
+	bolt "go.etcd.io/bbolt"
+)
+
+// StatusFilled is the status of orders that were sold.
+const StatusFilled = "filled"
+
+// GetOrder returns the latest archived state of an order, or ErrNotFound.
+func (s *Store) GetOrder(orderID int32) (*Entry, error) {
+	var entry *Entry
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		entry, err = getEntry(tx, orderKey(orderID))
+		return err
+	})
+
+	return entry, err
+}
+
+// History returns the recorded changes of an order, oldest first.
+func (s *Store) History(orderID int32) ([]Change, error) {
+	var changes []Change
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(changesBucket).Bucket([]byte(orderKey(orderID)))
+		if b == nil {
+			return ErrNotFound
+		}
+
+		return b.ForEach(func(k, v []byte) error {
+			var change Change
+			if err := json.Unmarshal(v, &change); err != nil {
+				return err
+			}
+
+			changes = append(changes, change)
+			return nil
+		})
+	})
+
+	return changes, err
+}
+
+// OrdersForToken returns the archived orders buying or selling a token, with
+// any of the given statuses or all of them, in order ID order. tokenAddress
+// may be a collection shortcut.
+func (s *Store) OrdersForToken(tokenAddress, tokenID string, statuses ...string) ([]Entry, error) {
+	tokenAddress = collections.ResolveCollection("token address", tokenAddress)
+	return s.scan(tokensBucket, tokenPrefix(tokenAddress, tokenID), statuses)
+}
+
+// Sales returns the filled orders of a token.
+func (s *Store) Sales(tokenAddress, tokenID string) ([]Entry, error) {
+	return s.OrdersForToken(tokenAddress, tokenID, StatusFilled)
+}
+
+// OrdersByUser returns the archived orders created by a user, with any of the
+// given statuses or all of them, in order ID order. user may be a wallet
+// alias.
+func (s *Store) OrdersByUser(user string, statuses ...string) ([]Entry, error) {
+	user = collections.ResolveWallet("user", user)
+	return s.scan(usersBucket, userPrefix(user), statuses)
+}
+
+func (s *Store) scan(bucket []byte, prefix string, statuses []string) ([]Entry, error) {
+	var entries []Entry
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+		for k, _ := c.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, _ = c.Next() {
+			entry, err := getEntry(tx, string(k[len(prefix):]))
+			if err != nil {
+				return err
+			}
+
+			if matchesStatus(entry.Order.Status, statuses) {
+				entries = append(entries, *entry)
+			}
+		}
+
+		return nil
+	})
+
+	return entries, err
+}
+
+func getEntry(tx *bolt.Tx, id string) (*Entry, error) {
+	data := tx.Bucket(ordersBucket).Get([]byte(id))
+	if data == nil {
+		return nil, ErrNotFound
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("corrupt archive entry %s: %w", id, err)
+	}
+
+	return &entry, nil
+}
+
+func matchesStatus(status string, statuses []string) bool {
+	if len(statuses) == 0 {
+		return true
+	}
+
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,237 @@
+// Package archive keeps a local history of orders, recording every status
+// change seen while syncing, so filled, cancelled and expired orders can be
+// queried after the API stops returning them in active listings.
+package archive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/utils"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	ErrNotFound = errors.New("not found in archive")
+
+	ordersBucket  = []byte("orders")
+	changesBucket = []byte("changes")
+	tokensBucket  = []byte("tokens")
+	usersBucket   = []byte("users")
+	streamsBucket = []byte("streams")
+)
+
+// Entry is the latest known state of an order. An order is only replaced by
+// one with the same or a later updated timestamp.
+type Entry struct {
+	Order     api.Order `json:"order"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// Change is a recorded state of an order. A change is recorded when an order is
+// first seen and whenever its status or updated timestamp differs from the
+// previous change.
+type Change struct {
+	Status           string    `json:"status"`
+	UpdatedTimestamp string    `json:"updated_timestamp"`
+	ObservedAt       time.Time `json:"observed_at"`
+	Order            api.Order `json:"order"`
+}
+
+// StreamState tracks how far a stream of orders has been synced.
+type StreamState struct {
+	Stream Stream `json:"stream"`
+	utils.SyncCheckpoint
+}
+
+type Store struct {
+	db *bolt.DB
+}
+
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("could not open archive %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{ordersBucket, changesBucket, tokensBucket, usersBucket, streamsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// orderKey pads order IDs so keys sort numerically.
+func orderKey(id int32) string {
+	return fmt.Sprintf("%010d", id)
+}
+
+func tokenPrefix(tokenAddress, tokenID string) string {
+	return strings.ToLower(tokenAddress) + "/" + tokenID + "/"
+}
+
+func userPrefix(user string) string {
+	return strings.ToLower(user) + "/"
+}
+
+// StreamState returns the sync state of a stream, which is empty if it has
+// never been synced.
+func (s *Store) StreamState(stream Stream) (StreamState, error) {
+	state := StreamState{Stream: stream}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(streamsBucket).Get([]byte(stream.key()))
+		if data == nil {
+			return nil
+		}
+
+		return json.Unmarshal(data, &state)
+	})
+
+	return state, err
+}
+
+// Streams returns the sync state of every synced stream.
+func (s *Store) Streams() ([]StreamState, error) {
+	var states []StreamState
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(streamsBucket).ForEach(func(k, v []byte) error {
+			var state StreamState
+			if err := json.Unmarshal(v, &state); err != nil {
+				return err
+			}
+
+			states = append(states, state)
+			return nil
+		})
+	})
+
+	return states, err
+}
+
+// apply stores a page of orders and the stream state after it in one
+// transaction, returning how many changes were recorded.
+func (s *Store) apply(state *StreamState, page []api.Order, now time.Time) (changes int, err error) {
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		for _, order := range page {
+			changed, err := putOrder(tx, order, now)
+			if err != nil {
+				return err
+			}
+
+			if changed {
+				changes++
+			}
+
+			if updated := order.GetUpdatedTimestamp(); utils.LaterTimestamp(updated, state.UpdatedAt) {
+				state.UpdatedAt = updated
+			}
+		}
+
+		data, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(streamsBucket).Put([]byte(state.Stream.key()), data)
+	})
+
+	return changes, err
+}
+
+func putOrder(tx *bolt.Tx, order api.Order, now time.Time) (bool, error) {
+	id := orderKey(order.OrderId)
+	orders := tx.Bucket(ordersBucket)
+
+	entry := Entry{Order: order, FirstSeen: now, LastSeen: now}
+	changed := true
+	if data := orders.Get([]byte(id)); data != nil {
+		var prev Entry
+		if err := json.Unmarshal(data, &prev); err != nil {
+			return false, fmt.Errorf("corrupt archive entry %s: %w", id, err)
+		}
+
+		entry.FirstSeen = prev.FirstSeen
+		changed = prev.Order.Status != order.Status || prev.Order.GetUpdatedTimestamp() != order.GetUpdatedTimestamp()
+
+		// A page fetched before the last change, e.g. by a stream that's
+		// behind, mustn't roll the order back.
+		if utils.LaterTimestamp(prev.Order.GetUpdatedTimestamp(), order.GetUpdatedTimestamp()) {
+			entry.Order = prev.Order
+			changed = false
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return false, err
+	}
+
+	if err := orders.Put([]byte(id), data); err != nil {
+		return false, err
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	history, err := tx.Bucket(changesBucket).CreateBucketIfNotExists([]byte(id))
+	if err != nil {
+		return false, err
+	}
+
+	seq, err := history.NextSequence()
+	if err != nil {
+		return false, err
+	}
+
+	change := Change{
+		Status:           order.Status,
+		UpdatedTimestamp: order.GetUpdatedTimestamp(),
+		ObservedAt:       now,
+		Order:            order,
+	}
+
+	if data, err = json.Marshal(change); err != nil {
+		return false, err
+	}
+
+	if err := history.Put([]byte(fmt.Sprintf("%020d", seq)), data); err != nil {
+		return false, err
+	}
+
+	for _, side := range []api.OrderInfo{order.GetSell(), order.GetBuy()} {
+		if side.Data.GetTokenId() == "" {
+			continue
+		}
+
+		key := tokenPrefix(side.Data.GetTokenAddress(), side.Data.GetTokenId()) + id
+		if err := tx.Bucket(tokensBucket).Put([]byte(key), nil); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Bucket(usersBucket).Put([]byte(userPrefix(order.User)+id), nil); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
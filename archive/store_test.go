@@ -0,0 +1,149 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+const heroAddress = "0x6465ef3009f3c474774f4afb607a5d600ea71d95"
+
+func openStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := Open(filepath.Join(t.TempDir(), "archive.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	return s
+}
+
+func orderAt(t *testing.T, id int32, status, updated string) api.Order {
+	t.Helper()
+
+	var o api.Order
+	data := fmt.Sprintf(`{"order_id": %d, "status": %q, "user": "0x1111111111111111111111111111111111111111", "updated_timestamp": %q,
+		"sell": {"type": "ERC721", "data": {"token_address": %q, "token_id": "1"}},
+		"buy": {"type": "ETH", "data": {"quantity": "1000000000000000"}}}`, id, status, updated, heroAddress)
+	if err := json.Unmarshal([]byte(data), &o); err != nil {
+		t.Fatal(err)
+	}
+
+	return o
+}
+
+func TestApplyKeepsNewerOrders(t *testing.T) {
+	s := openStore(t)
+	state := &StreamState{}
+	start := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	pages := []struct {
+		order       api.Order
+		wantStatus  string
+		wantChanges int
+	}{
+		{orderAt(t, 1, "active", "2022-04-01T00:00:00Z"), "active", 1},
+		{orderAt(t, 1, "filled", "2022-04-03T00:00:00Z"), "filled", 1},
+		// A stale page doesn't roll the order back or record a change.
+		{orderAt(t, 1, "active", "2022-04-02T00:00:00Z"), "filled", 0},
+		{orderAt(t, 1, "filled", "2022-04-03T00:00:00Z"), "filled", 0},
+	}
+
+	for i, p := range pages {
+		now := start.Add(time.Duration(i) * time.Hour)
+		changes, err := s.apply(state, []api.Order{p.order}, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry, err := s.GetOrder(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if entry.Order.Status != p.wantStatus || changes != p.wantChanges {
+			t.Errorf("page %d: got status %s and %d changes, want %s and %d", i, entry.Order.Status, changes, p.wantStatus, p.wantChanges)
+		}
+
+		if !entry.FirstSeen.Equal(start) || !entry.LastSeen.Equal(now) {
+			t.Errorf("page %d: got first seen %v and last seen %v", i, entry.FirstSeen, entry.LastSeen)
+		}
+	}
+
+	history, err := s.History(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var statuses []string
+	for _, change := range history {
+		statuses = append(statuses, change.Status)
+	}
+
+	if len(statuses) != 2 || statuses[0] != "active" || statuses[1] != "filled" {
+		t.Errorf("got history %v, want active then filled", statuses)
+	}
+
+	if state.UpdatedAt != "2022-04-03T00:00:00Z" {
+		t.Errorf("got watermark %s", state.UpdatedAt)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	s := openStore(t)
+	page := []api.Order{
+		orderAt(t, 1, "active", "2022-04-01T00:00:00Z"),
+		orderAt(t, 2, "filled", "2022-04-02T00:00:00Z"),
+	}
+
+	if _, err := s.apply(&StreamState{}, page, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query func() ([]Entry, error)
+		want  []int32
+	}{
+		{"orders for token", func() ([]Entry, error) { return s.OrdersForToken("hero", "1") }, []int32{1, 2}},
+		{"sales", func() ([]Entry, error) { return s.Sales(heroAddress, "1") }, []int32{2}},
+		{"other token", func() ([]Entry, error) { return s.OrdersForToken("hero", "2") }, nil},
+		{"orders by user", func() ([]Entry, error) {
+			return s.OrdersByUser("0x1111111111111111111111111111111111111111", "active")
+		}, []int32{1}},
+	}
+
+	for _, tt := range tests {
+		entries, err := tt.query()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		var got []int32
+		for _, e := range entries {
+			got = append(got, e.Order.OrderId)
+		}
+
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got orders %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got orders %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+
+	if _, err := s.GetOrder(3); err != ErrNotFound {
+		t.Errorf("got %v for a missing order, want ErrNotFound", err)
+	}
+}
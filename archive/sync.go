@@ -0,0 +1,140 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/deadloct/immutablex-go-lib/utils"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+	log "github.com/sirupsen/logrus"
+)
+
+const DefaultSyncPageSize = 200
+
+// DefaultStatuses are the order statuses synced by SyncAll.
+var DefaultStatuses = []string{"active", "filled", "cancelled", "expired", "inactive"}
+
+// Stream is a filtered view of orders synced with its own watermark, e.g. the
+// filled orders selling tokens of one collection. Empty fields aren't filtered.
+type Stream struct {
+	SellTokenAddress string `json:"sell_token_address,omitempty"`
+	BuyTokenAddress  string `json:"buy_token_address,omitempty"`
+	User             string `json:"user,omitempty"`
+	Status           string `json:"status,omitempty"`
+}
+
+// key identifies the stream's state, resolving shortcuts so a stream shares
+// its watermark with the same stream given by address.
+func (s Stream) key() string {
+	return strings.ToLower(strings.Join([]string{
+		collections.ResolveCollection("sell token address", s.SellTokenAddress),
+		collections.ResolveCollection("buy token address", s.BuyTokenAddress),
+		collections.ResolveWallet("user", s.User),
+		s.Status,
+	}, "|"))
+}
+
+type Archiver struct {
+	client   orders.Client
+	store    *Store
+	pageSize int
+}
+
+// NewArchiver archives orders into store using client, fetching pageSize
+// orders per request, or DefaultSyncPageSize if pageSize isn't positive.
+func NewArchiver(client orders.Client, store *Store, pageSize int) *Archiver {
+	if pageSize <= 0 {
+		pageSize = DefaultSyncPageSize
+	}
+
+	return &Archiver{client: client, store: store, pageSize: pageSize}
+}
+
+type SyncResult struct {
+	Pages   int
+	Orders  int
+	Changes int
+}
+
+// Sync fetches the orders of a stream updated since its last sync, see
+// utils.SyncPages.
+func (a *Archiver) Sync(ctx context.Context, stream Stream) (*SyncResult, error) {
+	state, err := a.store.StreamState(stream)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.Cursor != "" {
+		log.Debugf("resuming archive of %s from cursor %s", stream.key(), state.Cursor)
+	}
+
+	cfg := orders.ListOrdersConfig{
+		BuyTokenAddress:  stream.BuyTokenAddress,
+		Direction:        "asc",
+		IncludeFees:      true,
+		OrderBy:          "updated_at",
+		PageSize:         a.pageSize,
+		SellTokenAddress: stream.SellTokenAddress,
+		Status:           stream.Status,
+		User:             stream.User,
+	}
+
+	fetch := func(ctx context.Context, queryFrom, cursor string) ([]api.Order, string, error) {
+		cfg.Orders = nil
+		cfg.UpdatedMinTimestamp = queryFrom
+		cfg.Cursor = cursor
+		page, err := a.client.ListOrders(ctx, &cfg)
+		return page, cfg.Cursor, err
+	}
+
+	result := &SyncResult{}
+	store := func(page []api.Order, now time.Time) error {
+		changes, err := a.store.apply(&state, page, now)
+		if err != nil {
+			return err
+		}
+
+		result.Pages++
+		result.Orders += len(page)
+		result.Changes += changes
+		log.Debugf("archived page %d of %s: %d orders, %d changes", result.Pages, stream.key(), len(page), changes)
+		return nil
+	}
+
+	if _, err := utils.SyncPages(ctx, &state.SyncCheckpoint, a.pageSize, fetch, store); err != nil {
+		return result, fmt.Errorf("archive of %s failed after %d pages: %w", stream.key(), result.Pages, err)
+	}
+
+	return result, nil
+}
+
+// SyncAll syncs one stream per status, using base for the other filters. When
+// no statuses are given DefaultStatuses are used.
+func (a *Archiver) SyncAll(ctx context.Context, base Stream, statuses ...string) (*SyncResult, error) {
+	if len(statuses) == 0 {
+		statuses = DefaultStatuses
+	}
+
+	total := &SyncResult{}
+	for _, status := range statuses {
+		stream := base
+		stream.Status = status
+
+		result, err := a.Sync(ctx, stream)
+		if result != nil {
+			total.Pages += result.Pages
+			total.Orders += result.Orders
+			total.Changes += result.Changes
+		}
+
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
@@ -0,0 +1,78 @@
+package archive
+
+import (
+	"context"
+	"testing"
+
+	"github.com/deadloct/immutablex-go-lib/imxtest"
+	"github.com/deadloct/immutablex-go-lib/orders"
+)
+
+func TestSync(t *testing.T) {
+	s := openStore(t)
+	client := imxtest.NewOrdersClient(imxtest.DefaultFixtures())
+	a := NewArchiver(client, s, 1)
+
+	result, err := a.SyncAll(context.Background(), Stream{SellTokenAddress: "hero"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Orders 1 and 2 are active and 3 is filled. Each stream ends with a short
+	// page.
+	if result.Orders != 3 || result.Changes != 3 || result.Pages != 3+len(DefaultStatuses) {
+		t.Errorf("got %+v", result)
+	}
+
+	// The shortcut and the address share a watermark.
+	state, err := s.StreamState(Stream{SellTokenAddress: heroAddress, Status: "active"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.UpdatedAt != "2022-04-02T12:00:00Z" || state.Cursor != "" {
+		t.Errorf("got state %+v", state)
+	}
+
+	// The next sync only fetches what's updated since the watermark, which is
+	// already archived.
+	result, err = a.Sync(context.Background(), Stream{SellTokenAddress: heroAddress, Status: "active"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Orders != 1 || result.Changes != 0 {
+		t.Errorf("got %+v resyncing, want only the order at the watermark", result)
+	}
+
+	calls := client.CallsTo("ListOrders")
+	if got := calls[len(calls)-1].Args[0].(orders.ListOrdersConfig).UpdatedMinTimestamp; got != "2022-04-02T12:00:00Z" {
+		t.Errorf("got updated min timestamp %q, want the watermark", got)
+	}
+}
+
+func TestSyncDoesntRollBack(t *testing.T) {
+	s := openStore(t)
+	f := imxtest.DefaultFixtures()
+	client := imxtest.NewOrdersClient(f)
+
+	filled, updated := f.Orders[0], "2022-05-01T12:00:00Z"
+	filled.Status = "filled"
+	filled.UpdatedTimestamp.Set(&updated)
+	client.AddOrders(filled)
+
+	// The filled stream is synced first, so the active stream is behind it.
+	a := NewArchiver(client, s, 0)
+	if _, err := a.SyncAll(context.Background(), Stream{User: "0x1111111111111111111111111111111111111111"}, "filled", "active"); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := s.GetOrder(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if entry.Order.Status != "filled" {
+		t.Errorf("got status %s, want filled", entry.Order.Status)
+	}
+}
@@ -2,26 +2,38 @@ package assets
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	"github.com/deadloct/immutablex-go-lib/collections"
 	"github.com/deadloct/immutablex-go-lib/imx"
+	"github.com/deadloct/immutablex-go-lib/metrics"
 	"github.com/immutable/imx-core-sdk-golang/imx/api"
 	log "github.com/sirupsen/logrus"
 )
 
 type AlchemyClientConfig struct {
 	alchemyKey string
+
+	// HTTPClient is passed to the SDK, its default is used when nil.
+	HTTPClient *http.Client
+
+	// Client replaces the SDK client wrapper, e.g. with a fake in tests.
+	Client imx.ClientWrapper
 }
 
 type AlchemyClient struct {
-	client    imx.ClientWrapper
-	shortcuts collections.Shortcuts
+	client imx.ClientWrapper
 }
 
 func NewAlchemyClient(cfg AlchemyClientConfig) *AlchemyClient {
+	var client imx.ClientWrapper = cfg.Client
+	if client == nil {
+		client = imx.NewClientWithHTTPClient(cfg.alchemyKey, cfg.HTTPClient)
+	}
+
 	return &AlchemyClient{
-		client:    imx.NewClient(cfg.alchemyKey),
-		shortcuts: collections.NewShortcuts(),
+		client: client,
 	}
 }
 
@@ -34,25 +46,40 @@ func (am *AlchemyClient) Stop() {
 }
 
 func (am *AlchemyClient) GetAsset(ctx context.Context, tokenAddress, tokenID string, includeFees bool) (*api.Asset, error) {
-	if s := am.shortcuts.GetShortcutByName(tokenAddress); s != nil {
-		tokenAddress = s.Addr
-	}
+	tokenAddress = collections.ResolveCollection("token address", tokenAddress)
 
 	log.Debugf("fetching asset id %s from collection %s (with fees:%t)", tokenAddress, tokenID, includeFees)
-	return am.client.GetClient().GetAsset(ctx, tokenAddress, tokenID, &includeFees)
+	// Only send include_fees when set, like the REST client.
+	var fees *bool
+	if includeFees {
+		fees = &includeFees
+	}
+
+	start := time.Now()
+	asset, err := am.client.GetClient().GetAsset(metrics.WithEndpoint(ctx, metrics.EndpointGetAsset), tokenAddress, tokenID, fees)
+	metrics.ObserveCall(metrics.EndpointGetAsset, start, err)
+	return asset, err
+}
+
+func (am *AlchemyClient) ListAssets(ctx context.Context, cfg ListAssetsConfig) ([]api.AssetWithOrders, error) {
+	return am.ListAssetsPage(ctx, &cfg)
 }
 
-func (am *AlchemyClient) ListAssets(
+func (am *AlchemyClient) ListAssetsPage(
 	ctx context.Context,
-	cfg ListAssetsConfig,
+	cfg *ListAssetsConfig,
 ) ([]api.AssetWithOrders, error) {
 
-	req := am.getAPIListAssetsRequest(ctx, cfg)
+	req := am.getAPIListAssetsRequest(metrics.WithEndpoint(ctx, metrics.EndpointListAssets), cfg)
+	start := time.Now()
 	resp, err := am.client.GetClient().ListAssets(&req)
+	metrics.ObserveCall(metrics.EndpointListAssets, start, err)
 	if err != nil {
 		return nil, err
 	}
 
+	metrics.GetRecorder().Page(metrics.EndpointListAssets, len(resp.Result))
+
 	if len(resp.Result) == 0 {
 		return cfg.Assets, nil
 	}
@@ -60,29 +87,29 @@ func (am *AlchemyClient) ListAssets(
 	cfg.Assets = append(cfg.Assets, resp.Result...)
 	cfg.Cursor = resp.Cursor
 
-	first := *resp.Result[0].UpdatedAt.Get()
-	last := *resp.Result[len(resp.Result)-1].UpdatedAt.Get()
+	first := resp.Result[0].GetUpdatedAt()
+	last := resp.Result[len(resp.Result)-1].GetUpdatedAt()
 	log.Debugf("fetched %v assets from %v to %v", len(resp.Result), first, last)
 
-	if resp.Remaining > 0 {
-		return am.ListAssets(ctx, cfg)
+	getMore := len(cfg.Assets) < cfg.PageSize || cfg.PageSize == 0
+	if resp.Remaining > 0 && getMore {
+		return am.ListAssetsPage(ctx, cfg)
 	}
 
 	return cfg.Assets, nil
 }
 
-func (am *AlchemyClient) getAPIListAssetsRequest(ctx context.Context, cfg ListAssetsConfig) api.ApiListAssetsRequest {
-	collectionAddr := cfg.Collection
-	if s := am.shortcuts.GetShortcutByName(collectionAddr); s != nil {
-		collectionAddr = s.Addr
-	}
-
-	req := am.client.GetClient().NewListAssetsRequest(ctx).Collection(collectionAddr)
+func (am *AlchemyClient) getAPIListAssetsRequest(ctx context.Context, cfg *ListAssetsConfig) api.ApiListAssetsRequest {
+	req := am.client.GetClient().NewListAssetsRequest(ctx)
 
 	if cfg.BuyOrders {
 		req = req.BuyOrders(cfg.BuyOrders)
 	}
 
+	if collectionAddr := collections.ResolveCollection("collection", cfg.Collection); collectionAddr != "" {
+		req = req.Collection(collectionAddr)
+	}
+
 	if cfg.Cursor != "" {
 		req = req.Cursor(cfg.Cursor)
 	}
@@ -100,13 +127,17 @@ func (am *AlchemyClient) getAPIListAssetsRequest(ctx context.Context, cfg ListAs
 	}
 
 	if cfg.Name != "" {
-		req = req.IncludeFees(cfg.IncludeFees)
+		req = req.Name(cfg.Name)
 	}
 
 	if cfg.OrderBy != "" {
-		req.OrderBy(cfg.OrderBy)
+		req = req.OrderBy(cfg.OrderBy)
 	} else {
-		req.OrderBy("updated_at")
+		req = req.OrderBy(DefaultOrderBy)
+	}
+
+	if cfg.PageSize > 0 {
+		req = req.PageSize(int32(cfg.PageSize - len(cfg.Assets)))
 	}
 
 	if cfg.SellOrders {
@@ -126,7 +157,7 @@ func (am *AlchemyClient) getAPIListAssetsRequest(ctx context.Context, cfg ListAs
 	}
 
 	if cfg.User != "" {
-		req = req.User(cfg.User)
+		req = req.User(collections.ResolveWallet("user", cfg.User))
 	}
 
 	return req
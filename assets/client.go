@@ -2,12 +2,17 @@ package assets
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/deadloct/immutablex-go-lib/utils"
 	"github.com/immutable/imx-core-sdk-golang/imx/api"
 	log "github.com/sirupsen/logrus"
 )
 
+// DefaultOrderBy is used when ListAssetsConfig.OrderBy is empty, so pages
+// are stable while assets change.
+const DefaultOrderBy = "updated_at"
+
 type ListAssetsConfig struct {
 	BuyOrders           bool
 	Collection          string
@@ -16,13 +21,16 @@ type ListAssetsConfig struct {
 	Metadata            string
 	Name                string
 	OrderBy             string
+	PageSize            int
 	SellOrders          bool
 	Status              string
 	UpdatedMaxTimestamp string
 	UpdatedMinTimestamp string
 	User                string
 
-	// Used internally for recursion
+	// Used internally for recursion. When PageSize is set, ListAssetsPage
+	// leaves Cursor at the last page fetched so the next call continues from
+	// there.
 	Assets []api.AssetWithOrders
 	Cursor string
 }
@@ -34,12 +42,44 @@ type Client interface {
 	ListAssets(ctx context.Context, cfg ListAssetsConfig) ([]api.AssetWithOrders, error)
 }
 
+// Pager is implemented by clients that can continue listing assets where a
+// previous call stopped. ListAssetsPage is ListAssets, except that it appends
+// to cfg.Assets and leaves cfg.Cursor at the last page fetched.
+type Pager interface {
+	ListAssetsPage(ctx context.Context, cfg *ListAssetsConfig) ([]api.AssetWithOrders, error)
+}
+
+// ListAssetsPage lists a page of assets with client, see Pager. Clients that
+// don't implement Pager can't continue, so cfg.Cursor is cleared and the
+// results are the only page.
+func ListAssetsPage(ctx context.Context, client Client, cfg *ListAssetsConfig) ([]api.AssetWithOrders, error) {
+	if p, ok := client.(Pager); ok {
+		return p.ListAssetsPage(ctx, cfg)
+	}
+
+	result, err := client.ListAssets(ctx, *cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Assets = append(cfg.Assets, result...)
+	cfg.Cursor = ""
+	return cfg.Assets, nil
+}
+
 func NewClientConfig(alchemyKey string) interface{} {
+	return NewClientConfigWithHTTPClient(alchemyKey, nil)
+}
+
+// NewClientConfigWithHTTPClient is NewClientConfig with the HTTP client every
+// request is sent with, the default is used when nil. Wrap its transport in a
+// utils.RetryTransport to retry requests the API is too busy for.
+func NewClientConfigWithHTTPClient(alchemyKey string, httpClient *http.Client) interface{} {
 	if alchemyKey == "" {
-		return RESTClientConfig{url: utils.DefaultImmutableAPIURL}
+		return RESTClientConfig{URL: utils.DefaultImmutableAPIURL, HTTPClient: httpClient}
 	}
 
-	return AlchemyClientConfig{alchemyKey: alchemyKey}
+	return AlchemyClientConfig{alchemyKey: alchemyKey, HTTPClient: httpClient}
 }
 
 func NewClient(cfg interface{}) Client {
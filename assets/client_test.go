@@ -0,0 +1,53 @@
+package assets_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/imxtest"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+// listOnly is an assets.Client that doesn't implement assets.Pager.
+type listOnly struct {
+	assets.Client
+}
+
+func (c listOnly) ListAssets(ctx context.Context, cfg assets.ListAssetsConfig) ([]api.AssetWithOrders, error) {
+	return c.Client.ListAssets(ctx, cfg)
+}
+
+func TestListAssetsPage(t *testing.T) {
+	ctx := context.Background()
+	client := imxtest.NewAssetsClient(imxtest.DefaultFixtures())
+
+	cfg := assets.ListAssetsConfig{Collection: "hero", PageSize: 3}
+	first, err := assets.ListAssetsPage(ctx, client, &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 3 || cfg.Cursor == "" {
+		t.Fatalf("got %d assets and cursor %q, want 3 and a cursor", len(first), cfg.Cursor)
+	}
+
+	cursor := cfg.Cursor
+	cfg.Assets = nil
+	rest, err := assets.ListAssetsPage(ctx, client, &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 1 || rest[0].TokenId == first[0].TokenId {
+		t.Errorf("continuing returned %d assets", len(rest))
+	}
+
+	// Clients without ListAssetsPage return one page without a cursor.
+	cfg = assets.ListAssetsConfig{Collection: "hero", PageSize: 3, Cursor: cursor}
+	result, err := assets.ListAssetsPage(ctx, listOnly{client}, &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 || cfg.Cursor != "" || len(cfg.Assets) != 1 {
+		t.Errorf("got %d assets and cursor %q, want 1 and no cursor", len(result), cfg.Cursor)
+	}
+}
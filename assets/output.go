@@ -3,24 +3,46 @@ package assets
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
+	"github.com/deadloct/immutablex-go-lib/output"
 	"github.com/deadloct/immutablex-go-lib/utils"
 	"github.com/immutable/imx-core-sdk-golang/imx/api"
 	log "github.com/sirupsen/logrus"
 )
 
-func PrintAssetJSON[T any](asset T) {
+// DefaultFields are the fields used by the tabular output formats when none
+// are selected.
+var DefaultFields = []string{"token_id", "name", "status", "user", "immutascan_url"}
+
+func WriteAssetJSON[T any](w io.Writer, asset T) error {
 	data, err := json.MarshalIndent(asset, "", "  ")
 	if err != nil {
-		log.Debugf("could not convert asset to json: %v\nasset: %#v\n", err, asset)
-		return
+		return fmt.Errorf("could not convert asset to json: %w", err)
 	}
 
-	fmt.Println(string(data))
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func PrintAssetJSON[T any](asset T) {
+	if err := WriteAssetJSON(os.Stdout, asset); err != nil {
+		log.Errorf("could not print asset: %v", err)
+	}
 }
 
-func printAssetCommon(name, status, id, tokenID, collectionAddr string) {
+func getAssetURL(collectionAddr, tokenID string) string {
+	return strings.Join([]string{
+		utils.ImmutascanURL,
+		"address",
+		collectionAddr,
+		tokenID,
+	}, "/")
+}
+
+func writeAssetCommon(w io.Writer, name, status, id, tokenID, collectionAddr string) error {
 	if name == "" {
 		name = "[no name set]"
 	}
@@ -33,17 +55,13 @@ func printAssetCommon(name, status, id, tokenID, collectionAddr string) {
 		id = "[no id set]"
 	}
 
-	url := strings.Join([]string{
-		utils.ImmutascanURL,
-		"address",
-		collectionAddr,
-		tokenID,
-	}, "/")
-	fmt.Printf("%s (Status: %v): (%s)\n", name, status, url)
+	_, err := fmt.Fprintf(w, "%s (Status: %v): (%s)\n", name, status, getAssetURL(collectionAddr, tokenID))
+	return err
 }
 
-func PrintAssetWithOrdersStandard(collectionAddr string, asset *api.AssetWithOrders) {
-	printAssetCommon(
+func WriteAssetWithOrdersStandard(w io.Writer, collectionAddr string, asset *api.AssetWithOrders) error {
+	return writeAssetCommon(
+		w,
 		asset.GetName(),
 		asset.Status,
 		*asset.Id,
@@ -52,8 +70,15 @@ func PrintAssetWithOrdersStandard(collectionAddr string, asset *api.AssetWithOrd
 	)
 }
 
-func PrintAssetStandard(collectionAddr string, asset *api.Asset) {
-	printAssetCommon(
+func PrintAssetWithOrdersStandard(collectionAddr string, asset *api.AssetWithOrders) {
+	if err := WriteAssetWithOrdersStandard(os.Stdout, collectionAddr, asset); err != nil {
+		log.Errorf("could not print asset: %v", err)
+	}
+}
+
+func WriteAssetStandard(w io.Writer, collectionAddr string, asset *api.Asset) error {
+	return writeAssetCommon(
+		w,
 		asset.GetName(),
 		asset.Status,
 		*asset.Id,
@@ -62,22 +87,64 @@ func PrintAssetStandard(collectionAddr string, asset *api.Asset) {
 	)
 }
 
-func PrintAsset(collectionAddr string, asset *api.Asset, output string) {
-	switch strings.ToLower(output) {
-	case "json":
-		PrintAssetJSON(asset)
-	default:
-		PrintAssetStandard(collectionAddr, asset)
+func PrintAssetStandard(collectionAddr string, asset *api.Asset) {
+	if err := WriteAssetStandard(os.Stdout, collectionAddr, asset); err != nil {
+		log.Errorf("could not print asset: %v", err)
 	}
 }
 
-func PrintAssets(collectionAddr string, assets []api.AssetWithOrders, output string) {
-	for _, asset := range assets {
-		switch strings.ToLower(output) {
-		case "json":
-			PrintAssetJSON(asset)
-		default:
-			PrintAssetWithOrdersStandard(collectionAddr, &asset)
+func writeFormatted(w io.Writer, format string, single bool, items []interface{}, urls []string) error {
+	extra := func(i int) map[string]interface{} {
+		return map[string]interface{}{"immutascan_url": urls[i]}
+	}
+
+	return output.Write(w, format, single, items, DefaultFields, extra)
+}
+
+// WriteAsset writes the asset to w in the given format: "standard" (the
+// default) or any format registered with the output package, optionally
+// followed by the fields to include, e.g. "csv=token_id,name,metadata.rarity",
+// or a template such as "template={{.name}} {{truncate .user}}".
+func WriteAsset(w io.Writer, collectionAddr string, asset *api.Asset, format string) error {
+	if output.IsStandard(format) {
+		return WriteAssetStandard(w, collectionAddr, asset)
+	}
+
+	url := getAssetURL(collectionAddr, asset.TokenId)
+	return writeFormatted(w, format, true, []interface{}{asset}, []string{url})
+}
+
+// WriteAssets writes the assets to w in the given format, see WriteAsset.
+func WriteAssets(w io.Writer, collectionAddr string, assets []api.AssetWithOrders, format string) error {
+	if output.IsStandard(format) {
+		for _, asset := range assets {
+			if err := WriteAssetWithOrdersStandard(w, collectionAddr, &asset); err != nil {
+				return err
+			}
 		}
+		return nil
+	}
+
+	items := make([]interface{}, len(assets))
+	urls := make([]string, len(assets))
+	for i := range assets {
+		items[i] = assets[i]
+		urls[i] = getAssetURL(collectionAddr, assets[i].TokenId)
+	}
+
+	return writeFormatted(w, format, false, items, urls)
+}
+
+// PrintAsset prints the asset to stdout, see WriteAsset.
+func PrintAsset(collectionAddr string, asset *api.Asset, format string) {
+	if err := WriteAsset(os.Stdout, collectionAddr, asset, format); err != nil {
+		log.Errorf("could not print asset: %v", err)
+	}
+}
+
+// PrintAssets prints the assets to stdout, see WriteAsset.
+func PrintAssets(collectionAddr string, assets []api.AssetWithOrders, format string) {
+	if err := WriteAssets(os.Stdout, collectionAddr, assets, format); err != nil {
+		log.Errorf("could not print assets: %v", err)
 	}
 }
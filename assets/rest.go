@@ -2,12 +2,14 @@ package assets
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/metrics"
+	"github.com/deadloct/immutablex-go-lib/utils"
 	"github.com/immutable/imx-core-sdk-golang/imx/api"
 	log "github.com/sirupsen/logrus"
 )
@@ -18,20 +20,26 @@ const (
 )
 
 type RESTClientConfig struct {
-	url string
+	URL string
+
+	// HTTPClient sends every request, a new http.Client is used when nil.
+	HTTPClient *http.Client
 }
 
 type RESTClient struct {
-	client    *http.Client
-	url       string
-	shortcuts collections.Shortcuts
+	client *http.Client
+	url    string
 }
 
 func NewRESTClient(cfg RESTClientConfig) *RESTClient {
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+
 	return &RESTClient{
-		client:    &http.Client{},
-		url:       cfg.url,
-		shortcuts: collections.NewShortcuts(),
+		client: client,
+		url:    cfg.URL,
 	}
 }
 
@@ -42,21 +50,12 @@ func (c *RESTClient) Start() error {
 func (c *RESTClient) Stop() {}
 
 func (c *RESTClient) GetAsset(ctx context.Context, tokenAddress, tokenID string, includeFees bool) (*api.Asset, error) {
-	if s := c.shortcuts.GetShortcutByName(tokenAddress); s != nil {
-		tokenAddress = s.Addr
-	}
+	tokenAddress = collections.ResolveCollection("token address", tokenAddress)
 
 	log.Debugf("fetching asset id %s from collection %s (with fees:%t)", tokenAddress, tokenID, includeFees)
 	url := strings.Join([]string{c.url + GetAssetEndpoint, tokenAddress, tokenID}, "/")
-	resp, err := c.client.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var result api.Asset
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		log.Errorf("could not parse response from server: %#v", err)
+	if err := utils.GetJSON(ctx, c.client, metrics.EndpointGetAsset, url, &result); err != nil {
 		return nil, err
 	}
 
@@ -64,19 +63,18 @@ func (c *RESTClient) GetAsset(ctx context.Context, tokenAddress, tokenID string,
 }
 
 func (c *RESTClient) ListAssets(ctx context.Context, cfg ListAssetsConfig) ([]api.AssetWithOrders, error) {
-	url := c.getListAssetsURL(cfg)
-	getResp, err := c.client.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer getResp.Body.Close()
+	return c.ListAssetsPage(ctx, &cfg)
+}
 
+func (c *RESTClient) ListAssetsPage(ctx context.Context, cfg *ListAssetsConfig) ([]api.AssetWithOrders, error) {
+	url := c.getListAssetsURL(cfg)
 	var resp api.ListAssetsResponse
-	if err := json.NewDecoder(getResp.Body).Decode(&resp); err != nil {
-		log.Errorf("could not parse response from server: %#v", err)
+	if err := utils.GetJSON(ctx, c.client, metrics.EndpointListAssets, url, &resp); err != nil {
 		return nil, err
 	}
 
+	metrics.GetRecorder().Page(metrics.EndpointListAssets, len(resp.Result))
+
 	if len(resp.Result) == 0 {
 		return cfg.Assets, nil
 	}
@@ -84,28 +82,26 @@ func (c *RESTClient) ListAssets(ctx context.Context, cfg ListAssetsConfig) ([]ap
 	cfg.Assets = append(cfg.Assets, resp.Result...)
 	cfg.Cursor = resp.Cursor
 
-	first := *resp.Result[0].UpdatedAt.Get()
-	last := *resp.Result[len(resp.Result)-1].UpdatedAt.Get()
+	first := resp.Result[0].GetUpdatedAt()
+	last := resp.Result[len(resp.Result)-1].GetUpdatedAt()
 	log.Debugf("fetched %v assets from %v to %v", len(resp.Result), first, last)
 
-	if resp.Remaining > 0 {
-		return c.ListAssets(ctx, cfg)
+	getMore := len(cfg.Assets) < cfg.PageSize || cfg.PageSize == 0
+	if resp.Remaining > 0 && getMore {
+		return c.ListAssetsPage(ctx, cfg)
 	}
 
 	return cfg.Assets, nil
 }
 
-func (c *RESTClient) getListAssetsURL(cfg ListAssetsConfig) string {
+func (c *RESTClient) getListAssetsURL(cfg *ListAssetsConfig) string {
 	v := url.Values{}
 
 	if cfg.BuyOrders {
 		v.Set("buy_orders", "true")
 	}
 
-	collectionAddr := cfg.Collection
-	if s := c.shortcuts.GetShortcutByName(collectionAddr); s != nil {
-		collectionAddr = s.Addr
-	}
+	collectionAddr := collections.ResolveCollection("collection", cfg.Collection)
 
 	if collectionAddr != "" {
 		v.Set("collection", collectionAddr)
@@ -133,6 +129,12 @@ func (c *RESTClient) getListAssetsURL(cfg ListAssetsConfig) string {
 
 	if cfg.OrderBy != "" {
 		v.Set("order_by", cfg.OrderBy)
+	} else {
+		v.Set("order_by", DefaultOrderBy)
+	}
+
+	if cfg.PageSize > 0 {
+		v.Set("page_size", fmt.Sprint(cfg.PageSize-len(cfg.Assets)))
 	}
 
 	if cfg.SellOrders {
@@ -153,7 +155,7 @@ func (c *RESTClient) getListAssetsURL(cfg ListAssetsConfig) string {
 	}
 
 	if cfg.User != "" {
-		v.Set("user", cfg.User)
+		v.Set("user", collections.ResolveWallet("user", cfg.User))
 	}
 
 	return c.url + ListAssetsEndpoint + "?" + v.Encode()
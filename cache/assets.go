@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+// AssetsClient is an assets.Client that caches the responses of another.
+type AssetsClient struct {
+	client assets.Client
+	cache  *Cache
+}
+
+func NewAssetsClient(client assets.Client, cache *Cache) *AssetsClient {
+	return &AssetsClient{client: client, cache: cache}
+}
+
+func (c *AssetsClient) Start() error {
+	return c.client.Start()
+}
+
+func (c *AssetsClient) Stop() {
+	c.client.Stop()
+}
+
+func (c *AssetsClient) GetAsset(ctx context.Context, tokenAddress, tokenID string, includeFees bool) (*api.Asset, error) {
+	key := keyOf(EndpointGetAsset, normalizeAddress(tokenAddress), tokenID, boolKey(includeFees))
+	return cached(ctx, c.cache, EndpointGetAsset, key, func() (*api.Asset, error) {
+		return c.client.GetAsset(ctx, tokenAddress, tokenID, includeFees)
+	})
+}
+
+func (c *AssetsClient) ListAssets(ctx context.Context, cfg assets.ListAssetsConfig) ([]api.AssetWithOrders, error) {
+	return c.ListAssetsPage(ctx, &cfg)
+}
+
+func (c *AssetsClient) ListAssetsPage(ctx context.Context, cfg *assets.ListAssetsConfig) ([]api.AssetWithOrders, error) {
+	req := *cfg
+	req.Assets = nil
+	req.Collection = normalizeAddress(cfg.Collection)
+
+	hash, err := configKey(req)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keyOf(EndpointListAssets, normalizeAddress(cfg.Collection), hash)
+	entry, err := cached(ctx, c.cache, EndpointListAssets, key, func() (listEntry[api.AssetWithOrders], error) {
+		items, err := assets.ListAssetsPage(ctx, c.client, &req)
+		return listEntry[api.AssetWithOrders]{Items: items, Cursor: req.Cursor}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Assets = append(cfg.Assets, entry.Items...)
+	cfg.Cursor = entry.Cursor
+	return cfg.Assets, nil
+}
@@ -0,0 +1,226 @@
+// Package cache wraps the assets, collections and orders clients so Get and
+// List responses are served from a pluggable Backend until they expire.
+//
+//	c := cache.New(cache.Config{Backend: cache.NewMemoryBackend(0)})
+//	assetsClient := cache.NewAssetsClient(assets.NewClient(cfg), c)
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/metrics"
+	log "github.com/sirupsen/logrus"
+)
+
+const DefaultTTL = time.Minute
+
+// Endpoints, used as key prefixes and to configure per endpoint TTLs.
+const (
+	EndpointGetAsset        = "assets/get"
+	EndpointListAssets      = "assets/list"
+	EndpointGetCollection   = "collections/get"
+	EndpointListCollections = "collections/list"
+	EndpointGetOrder        = "orders/get"
+	EndpointListOrders      = "orders/list"
+)
+
+// Hooks are called as the cache is used, e.g. to export metrics. Any may be nil.
+type Hooks struct {
+	OnHit        func(endpoint, key string)
+	OnMiss       func(endpoint, key string)
+	OnInvalidate func(prefix string)
+}
+
+type Config struct {
+	Backend Backend
+	// DefaultTTL applies to endpoints missing from TTLs. A negative TTL in
+	// TTLs disables caching for that endpoint.
+	DefaultTTL time.Duration
+	TTLs       map[string]time.Duration
+	Hooks      Hooks
+}
+
+// Cache is shared by the client decorators, so invalidating an asset drops
+// both its Get and any List responses it may appear in.
+type Cache struct {
+	backend    Backend
+	defaultTTL time.Duration
+	ttls       map[string]time.Duration
+	hooks      Hooks
+}
+
+func New(cfg Config) *Cache {
+	c := &Cache{
+		backend:    cfg.Backend,
+		defaultTTL: cfg.DefaultTTL,
+		ttls:       cfg.TTLs,
+		hooks:      cfg.Hooks,
+	}
+
+	if c.backend == nil {
+		c.backend = NewMemoryBackend(0)
+	}
+
+	if c.defaultTTL <= 0 {
+		c.defaultTTL = DefaultTTL
+	}
+
+	return c
+}
+
+func (c *Cache) ttl(endpoint string) time.Duration {
+	if ttl, ok := c.ttls[endpoint]; ok {
+		return ttl
+	}
+
+	return c.defaultTTL
+}
+
+// InvalidateAsset drops a cached asset and every cached asset or order list,
+// since any of them may include it.
+func (c *Cache) InvalidateAsset(ctx context.Context, tokenAddress, tokenID string) error {
+	return c.invalidate(ctx,
+		keyOf(EndpointGetAsset, normalizeAddress(tokenAddress), tokenID)+"/",
+		keyOf(EndpointListAssets, normalizeAddress(tokenAddress))+"/",
+		EndpointListOrders+"/",
+	)
+}
+
+// InvalidateCollection drops a cached collection, its assets and every cached
+// collection or order list.
+func (c *Cache) InvalidateCollection(ctx context.Context, collection string) error {
+	addr := normalizeAddress(collection)
+	return c.invalidate(ctx,
+		keyOf(EndpointGetCollection, addr),
+		keyOf(EndpointGetAsset, addr)+"/",
+		keyOf(EndpointListAssets, addr)+"/",
+		EndpointListCollections+"/",
+		EndpointListOrders+"/",
+	)
+}
+
+// InvalidateOrder drops a cached order and every cached order list.
+func (c *Cache) InvalidateOrder(ctx context.Context, orderID string) error {
+	return c.invalidate(ctx, keyOf(EndpointGetOrder, orderID)+"/", EndpointListOrders+"/")
+}
+
+// InvalidateEndpoint drops every cached response of an endpoint.
+func (c *Cache) InvalidateEndpoint(ctx context.Context, endpoint string) error {
+	return c.invalidate(ctx, endpoint+"/")
+}
+
+// InvalidateAll empties the cache.
+func (c *Cache) InvalidateAll(ctx context.Context) error {
+	for _, prefix := range []string{"assets/", "collections/", "orders/"} {
+		if err := c.invalidate(ctx, prefix); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Cache) invalidate(ctx context.Context, prefixes ...string) error {
+	for _, prefix := range prefixes {
+		log.Debugf("invalidating cache prefix %s", prefix)
+
+		if err := c.backend.DeletePrefix(ctx, prefix); err != nil {
+			return err
+		}
+
+		if c.hooks.OnInvalidate != nil {
+			c.hooks.OnInvalidate(prefix)
+		}
+	}
+
+	return nil
+}
+
+// cached returns the value stored under key, or loads and stores it. Backend
+// failures are logged and otherwise treated as misses so a broken cache
+// doesn't break requests.
+func cached[T any](ctx context.Context, c *Cache, endpoint, key string, load func() (T, error)) (T, error) {
+	ttl := c.ttl(endpoint)
+	if ttl < 0 {
+		return load()
+	}
+
+	data, ok, err := c.backend.Get(ctx, key)
+	if err != nil {
+		log.Errorf("cache get %s failed: %v", key, err)
+	}
+
+	if ok {
+		var v T
+		if err := json.Unmarshal(data, &v); err == nil {
+			log.Debugf("cache hit %s", key)
+			metrics.GetRecorder().CacheHit(endpoint)
+			if c.hooks.OnHit != nil {
+				c.hooks.OnHit(endpoint, key)
+			}
+			return v, nil
+		}
+
+		log.Errorf("cache entry %s could not be decoded: %v", key, err)
+	}
+
+	log.Debugf("cache miss %s", key)
+	metrics.GetRecorder().CacheMiss(endpoint)
+	if c.hooks.OnMiss != nil {
+		c.hooks.OnMiss(endpoint, key)
+	}
+
+	v, err := load()
+	if err != nil {
+		return v, err
+	}
+
+	if data, err := json.Marshal(v); err != nil {
+		log.Errorf("could not encode cache entry %s: %v", key, err)
+	} else if err := c.backend.Set(ctx, key, data, ttl); err != nil {
+		log.Errorf("cache set %s failed: %v", key, err)
+	}
+
+	return v, nil
+}
+
+// listEntry is a cached page, with the cursor the wrapped client left in the
+// config so callers paging with PageSize continue from the right place.
+type listEntry[T any] struct {
+	Items  []T    `json:"items"`
+	Cursor string `json:"cursor"`
+}
+
+func keyOf(parts ...string) string {
+	return strings.Join(parts, "/")
+}
+
+// configKey hashes a list config. Callers clear the accumulated results first,
+// as they aren't part of the request.
+func configKey(cfg interface{}) (string, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("could not encode list config: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// normalizeAddress resolves shortcuts so they share entries with the addresses
+// they stand for.
+func normalizeAddress(addr string) string {
+	return strings.ToLower(collections.ResolveCollection("cache key", addr))
+}
+
+func boolKey(b bool) string {
+	return strconv.FormatBool(b)
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/imxtest"
+	"github.com/deadloct/immutablex-go-lib/orders"
+)
+
+func TestOrdersClientCachesResponses(t *testing.T) {
+	ctx := context.Background()
+	fake := imxtest.NewOrdersClient(imxtest.DefaultFixtures())
+	client := NewOrdersClient(fake, New(Config{}))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetOrder(ctx, "3", false); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := client.ListOrders(ctx, &orders.ListOrdersConfig{Status: "active"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := len(fake.CallsTo("GetOrder")); n != 1 {
+		t.Errorf("GetOrder called %d times, want 1", n)
+	}
+
+	if n := len(fake.CallsTo("ListOrders")); n != 1 {
+		t.Errorf("ListOrders called %d times, want 1", n)
+	}
+
+	if err := client.cache.InvalidateOrder(ctx, "3"); err != nil {
+		t.Fatal(err)
+	}
+
+	client.GetOrder(ctx, "3", false)
+	client.ListOrders(ctx, &orders.ListOrdersConfig{Status: "active"})
+
+	if n := len(fake.CallsTo("GetOrder")); n != 2 {
+		t.Errorf("GetOrder called %d times after invalidating, want 2", n)
+	}
+
+	if n := len(fake.CallsTo("ListOrders")); n != 2 {
+		t.Errorf("ListOrders called %d times after invalidating, want 2", n)
+	}
+}
+
+func TestEndpointTTLs(t *testing.T) {
+	ctx := context.Background()
+	fake := imxtest.NewOrdersClient(imxtest.DefaultFixtures())
+	client := NewOrdersClient(fake, New(Config{
+		TTLs: map[string]time.Duration{
+			EndpointGetOrder:   -1,
+			EndpointListOrders: time.Nanosecond,
+		},
+	}))
+
+	for i := 0; i < 2; i++ {
+		client.GetOrder(ctx, "3", false)
+		client.ListOrders(ctx, &orders.ListOrdersConfig{})
+		time.Sleep(time.Millisecond)
+	}
+
+	if n := len(fake.CallsTo("GetOrder")); n != 2 {
+		t.Errorf("GetOrder called %d times with caching disabled, want 2", n)
+	}
+
+	if n := len(fake.CallsTo("ListOrders")); n != 2 {
+		t.Errorf("ListOrders called %d times with expired entries, want 2", n)
+	}
+}
+
+func TestConfigKey(t *testing.T) {
+	a, err := configKey(orders.ListOrdersConfig{Status: "active"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := configKey(orders.ListOrdersConfig{Status: "filled"})
+	if a == b {
+		t.Error("different configs share a key")
+	}
+
+	if _, err := configKey(make(chan int)); err == nil {
+		t.Error("expected an error for a config that can't be encoded")
+	}
+}
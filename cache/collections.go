@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+// CollectionsClient is a collections.Client that caches the responses of
+// another.
+type CollectionsClient struct {
+	client collections.Client
+	cache  *Cache
+}
+
+func NewCollectionsClient(client collections.Client, cache *Cache) *CollectionsClient {
+	return &CollectionsClient{client: client, cache: cache}
+}
+
+func (c *CollectionsClient) Start() error {
+	return c.client.Start()
+}
+
+func (c *CollectionsClient) Stop() {
+	c.client.Stop()
+}
+
+func (c *CollectionsClient) GetCollection(ctx context.Context, collection string) (*api.Collection, error) {
+	key := keyOf(EndpointGetCollection, normalizeAddress(collection))
+	return cached(ctx, c.cache, EndpointGetCollection, key, func() (*api.Collection, error) {
+		return c.client.GetCollection(ctx, collection)
+	})
+}
+
+func (c *CollectionsClient) ListCollections(ctx context.Context, cfg *collections.ListCollectionsConfig) ([]api.Collection, error) {
+	req := *cfg
+	req.Collections = nil
+
+	hash, err := configKey(req)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keyOf(EndpointListCollections, hash)
+	entry, err := cached(ctx, c.cache, EndpointListCollections, key, func() (listEntry[api.Collection], error) {
+		items, err := c.client.ListCollections(ctx, &req)
+		return listEntry[api.Collection]{Items: items, Cursor: req.Cursor}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Collections = append(cfg.Collections, entry.Items...)
+	cfg.Cursor = entry.Cursor
+	return cfg.Collections, nil
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DiskBackend stores each entry in a file under a directory, mirroring the key
+// path so prefixes can be removed as directories. Each file starts with the
+// entry's expiry as Unix nanoseconds.
+type DiskBackend struct {
+	dir string
+}
+
+func NewDiskBackend(dir string) (*DiskBackend, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("could not create cache directory %s: %w", dir, err)
+	}
+
+	return &DiskBackend{dir: dir}, nil
+}
+
+// path maps key to a file under the cache directory. Segments come from
+// callers, e.g. order IDs, so "." and ".." are escaped as well to keep them
+// from walking out of it.
+func (d *DiskBackend) path(key string) string {
+	parts := strings.Split(strings.Trim(key, "/"), "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+		if parts[i] == "." || parts[i] == ".." {
+			parts[i] = strings.ReplaceAll(parts[i], ".", "%2E")
+		}
+	}
+
+	return filepath.Join(append([]string{d.dir}, parts...)...)
+}
+
+func (d *DiskBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, err := os.ReadFile(d.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	if len(data) < 8 {
+		return nil, false, fmt.Errorf("corrupt cache entry %s", key)
+	}
+
+	expires := time.Unix(0, int64(binary.BigEndian.Uint64(data[:8])))
+	if time.Now().After(expires) {
+		os.Remove(d.path(key))
+		return nil, false, nil
+	}
+
+	return data[8:], true, nil
+}
+
+func (d *DiskBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	path := d.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	data := make([]byte, 8+len(value))
+	binary.BigEndian.PutUint64(data[:8], uint64(time.Now().Add(ttl).UnixNano()))
+	copy(data[8:], value)
+
+	// Write to a temporary file first so readers never see a partial entry.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (d *DiskBackend) Delete(ctx context.Context, key string) error {
+	err := os.Remove(d.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+func (d *DiskBackend) DeletePrefix(ctx context.Context, prefix string) error {
+	return os.RemoveAll(d.path(prefix))
+}
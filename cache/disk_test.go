@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiskBackendKeysStayInDir(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	dir := filepath.Join(root, "a", "b", "cache")
+
+	d, err := NewDiskBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{
+		keyOf(EndpointGetOrder, "../../../x", boolKey(false)),
+		keyOf(EndpointGetOrder, "..", "..", "..", "..", "y"),
+		"../z",
+		"./.",
+	}
+
+	for _, key := range keys {
+		if err := d.Set(ctx, key, []byte(key), time.Minute); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+
+		data, ok, err := d.Get(ctx, key)
+		if err != nil || !ok || string(data) != key {
+			t.Errorf("get %s = %q, %t, %v", key, data, ok, err)
+		}
+	}
+
+	err = filepath.WalkDir(root, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !e.IsDir() && !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			t.Errorf("%s was written outside the cache dir", path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.DeletePrefix(ctx, "../.."); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("cache dir was removed: %v", err)
+	}
+}
+
+func TestDiskBackendTTL(t *testing.T) {
+	ctx := context.Background()
+	d, err := NewDiskBackend(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.Set(ctx, "assets/get/0xa/1", []byte("expired"), -time.Second)
+	d.Set(ctx, "assets/get/0xa/2", []byte("fresh"), time.Minute)
+
+	if _, ok, err := d.Get(ctx, "assets/get/0xa/1"); ok || err != nil {
+		t.Errorf("expired entry returned: %t, %v", ok, err)
+	}
+
+	if _, err := os.Stat(d.path("assets/get/0xa/1")); !os.IsNotExist(err) {
+		t.Errorf("expired entry not removed: %v", err)
+	}
+
+	data, ok, err := d.Get(ctx, "assets/get/0xa/2")
+	if !ok || err != nil || string(data) != "fresh" {
+		t.Errorf("got %q, %t, %v, want fresh", data, ok, err)
+	}
+}
+
+func TestDiskBackendDelete(t *testing.T) {
+	ctx := context.Background()
+	d, err := NewDiskBackend(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"assets/get/0xa/1", "assets/get/0xa/2", "assets/get/0xb/1", "orders/get/1"} {
+		d.Set(ctx, key, []byte(key), time.Minute)
+	}
+
+	if err := d.Delete(ctx, "orders/get/1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.Delete(ctx, "orders/get/missing"); err != nil {
+		t.Errorf("deleting a missing key: %v", err)
+	}
+
+	if err := d.DeletePrefix(ctx, "assets/get/0xa/"); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]bool{
+		"assets/get/0xa/1": false,
+		"assets/get/0xa/2": false,
+		"assets/get/0xb/1": true,
+		"orders/get/1":     false,
+	} {
+		if _, ok, _ := d.Get(ctx, key); ok != want {
+			t.Errorf("%s cached %t, want %t", key, ok, want)
+		}
+	}
+}
+
+func TestDiskBackendCorruptEntry(t *testing.T) {
+	ctx := context.Background()
+	d, err := NewDiskBackend(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(d.path("short"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := d.Get(ctx, "short"); ok || err == nil {
+		t.Errorf("got %t, %v, want an error", ok, err)
+	}
+}
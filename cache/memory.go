@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultMemoryEntries = 1000
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// MemoryBackend is an in-memory LRU cache whose entries also expire after their
+// TTL.
+type MemoryBackend struct {
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+
+	sync.Mutex
+}
+
+// NewMemoryBackend returns a backend holding at most maxEntries entries, or
+// DefaultMemoryEntries if maxEntries isn't positive.
+func NewMemoryBackend(maxEntries int) *MemoryBackend {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMemoryEntries
+	}
+
+	return &MemoryBackend{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+func (m *MemoryBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := el.Value.(*memoryEntry)
+	if time.Now().After(entry.expires) {
+		m.remove(el)
+		return nil, false, nil
+	}
+
+	m.lru.MoveToFront(el)
+	return entry.value, true, nil
+}
+
+func (m *MemoryBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.Lock()
+	defer m.Unlock()
+
+	entry := &memoryEntry{key: key, value: value, expires: time.Now().Add(ttl)}
+	if el, ok := m.entries[key]; ok {
+		el.Value = entry
+		m.lru.MoveToFront(el)
+		return nil
+	}
+
+	m.entries[key] = m.lru.PushFront(entry)
+	for m.lru.Len() > m.maxEntries {
+		m.remove(m.lru.Back())
+	}
+
+	return nil
+}
+
+func (m *MemoryBackend) Delete(ctx context.Context, key string) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if el, ok := m.entries[key]; ok {
+		m.remove(el)
+	}
+
+	return nil
+}
+
+func (m *MemoryBackend) DeletePrefix(ctx context.Context, prefix string) error {
+	m.Lock()
+	defer m.Unlock()
+
+	for key, el := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(el)
+		}
+	}
+
+	return nil
+}
+
+func (m *MemoryBackend) remove(el *list.Element) {
+	m.lru.Remove(el)
+	delete(m.entries, el.Value.(*memoryEntry).key)
+}
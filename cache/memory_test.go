@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryBackendEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryBackend(2)
+
+	m.Set(ctx, "a", []byte("a"), time.Minute)
+	m.Set(ctx, "b", []byte("b"), time.Minute)
+
+	// Reading a makes b the least recently used entry.
+	if _, ok, _ := m.Get(ctx, "a"); !ok {
+		t.Fatal("a missing")
+	}
+
+	m.Set(ctx, "c", []byte("c"), time.Minute)
+
+	if _, ok, _ := m.Get(ctx, "b"); ok {
+		t.Error("b should have been evicted")
+	}
+
+	for _, key := range []string{"a", "c"} {
+		if _, ok, _ := m.Get(ctx, key); !ok {
+			t.Errorf("%s should still be cached", key)
+		}
+	}
+}
+
+func TestMemoryBackendOverwrite(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryBackend(2)
+
+	m.Set(ctx, "a", []byte("old"), time.Minute)
+	m.Set(ctx, "a", []byte("new"), time.Minute)
+
+	data, ok, _ := m.Get(ctx, "a")
+	if !ok || string(data) != "new" {
+		t.Errorf("got %q, %t, want new", data, ok)
+	}
+
+	if m.lru.Len() != 1 {
+		t.Errorf("got %d entries, want 1", m.lru.Len())
+	}
+}
+
+func TestMemoryBackendTTL(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryBackend(0)
+
+	m.Set(ctx, "expired", []byte("x"), -time.Second)
+	m.Set(ctx, "fresh", []byte("x"), time.Minute)
+
+	if _, ok, _ := m.Get(ctx, "expired"); ok {
+		t.Error("expired entry returned")
+	}
+
+	if _, ok := m.entries["expired"]; ok {
+		t.Error("expired entry not removed")
+	}
+
+	if _, ok, _ := m.Get(ctx, "fresh"); !ok {
+		t.Error("fresh entry missing")
+	}
+}
+
+func TestMemoryBackendDelete(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryBackend(0)
+
+	for _, key := range []string{"assets/get/0xa/1", "assets/get/0xa/2", "assets/get/0xb/1", "orders/get/1"} {
+		m.Set(ctx, key, []byte(key), time.Minute)
+	}
+
+	m.Delete(ctx, "orders/get/1")
+	m.DeletePrefix(ctx, "assets/get/0xa/")
+
+	for key, want := range map[string]bool{
+		"assets/get/0xa/1": false,
+		"assets/get/0xa/2": false,
+		"assets/get/0xb/1": true,
+		"orders/get/1":     false,
+	} {
+		if _, ok, _ := m.Get(ctx, key); ok != want {
+			t.Errorf("%s cached %t, want %t", key, ok, want)
+		}
+	}
+}
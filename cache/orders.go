@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+// OrdersClient is an orders.Client that caches the responses of another.
+type OrdersClient struct {
+	client orders.Client
+	cache  *Cache
+}
+
+func NewOrdersClient(client orders.Client, cache *Cache) *OrdersClient {
+	return &OrdersClient{client: client, cache: cache}
+}
+
+func (c *OrdersClient) Start() error {
+	return c.client.Start()
+}
+
+func (c *OrdersClient) Stop() {
+	c.client.Stop()
+}
+
+func (c *OrdersClient) GetOrder(ctx context.Context, orderID string, includeFees bool) (*api.Order, error) {
+	key := keyOf(EndpointGetOrder, orderID, boolKey(includeFees))
+	return cached(ctx, c.cache, EndpointGetOrder, key, func() (*api.Order, error) {
+		return c.client.GetOrder(ctx, orderID, includeFees)
+	})
+}
+
+func (c *OrdersClient) ListOrders(ctx context.Context, cfg *orders.ListOrdersConfig) ([]api.Order, error) {
+	req := *cfg
+	req.Orders = nil
+
+	hash, err := configKey(req)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keyOf(EndpointListOrders, hash)
+	entry, err := cached(ctx, c.cache, EndpointListOrders, key, func() (listEntry[api.Order], error) {
+		items, err := c.client.ListOrders(ctx, &req)
+		return listEntry[api.Order]{Items: items, Cursor: req.Cursor}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Orders = append(cfg.Orders, entry.Items...)
+	cfg.Cursor = entry.Cursor
+	return cfg.Orders, nil
+}
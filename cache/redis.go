@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// RedisClient is the subset of Redis commands used by RedisBackend. Adapt your
+// Redis client of choice to it; Get must return found=false for missing keys.
+type RedisClient interface {
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	SetEX(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Del(ctx context.Context, keys ...string) error
+	// Scan returns all keys matching a glob pattern such as "prefix*".
+	Scan(ctx context.Context, pattern string) ([]string, error)
+}
+
+// RedisBackend stores entries in Redis under a key prefix, so several
+// applications can share a server.
+type RedisBackend struct {
+	client RedisClient
+	prefix string
+}
+
+func NewRedisBackend(client RedisClient, keyPrefix string) *RedisBackend {
+	return &RedisBackend{client: client, prefix: keyPrefix}
+}
+
+func (r *RedisBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return r.client.Get(ctx, r.prefix+key)
+}
+
+func (r *RedisBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.SetEX(ctx, r.prefix+key, value, ttl)
+}
+
+func (r *RedisBackend) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, r.prefix+key)
+}
+
+func (r *RedisBackend) DeletePrefix(ctx context.Context, prefix string) error {
+	keys, err := r.client.Scan(ctx, escapeGlob(r.prefix+prefix)+"*")
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return r.client.Del(ctx, keys...)
+}
+
+func escapeGlob(s string) string {
+	var out []rune
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			out = append(out, '\\')
+		}
+		out = append(out, r)
+	}
+
+	return string(out)
+}
@@ -0,0 +1,80 @@
+// Command imx-graphql serves a GraphQL API over Immutable X collections,
+// assets, orders and Coinbase prices, e.g.
+//
+//	{
+//	  collection(address: "hero") {
+//	    name
+//	    assets(first: 10, status: "imx") {
+//	      nodes {
+//	        name
+//	        sellOrders { nodes { price currency formattedFiatPrice(fiat: "EUR", locale: "de-DE") } }
+//	      }
+//	      pageInfo { endCursor hasNextPage }
+//	    }
+//	  }
+//	}
+//
+// The shortcuts file in IMX_SHORTCUT_LOCATION is reloaded when it changes.
+package main
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"os"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/graphql"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	var (
+		addr       = flag.String("addr", ":8081", "address to listen on")
+		alchemyKey = flag.String("alchemy-key", os.Getenv("IMX_ALCHEMY_KEY"), "Alchemy API key, uses the public REST API when empty")
+		debug      = flag.Bool("debug", false, "enable debug logging")
+	)
+	flag.Parse()
+
+	if *debug {
+		log.SetLevel(log.DebugLevel)
+	}
+
+	cfg := graphql.Config{
+		Assets:      assets.NewClient(assets.NewClientConfig(*alchemyKey)),
+		Collections: collections.NewClient(collections.NewClientConfig(*alchemyKey)),
+		Orders:      orders.NewClient(orders.NewClientConfig(*alchemyKey)),
+	}
+
+	for _, c := range []interface {
+		Start() error
+		Stop()
+	}{cfg.Assets, cfg.Collections, cfg.Orders} {
+		if err := c.Start(); err != nil {
+			log.Fatalf("could not start client: %v", err)
+		}
+		defer c.Stop()
+	}
+
+	collections.LoadShortcuts(context.Background())
+
+	watcher, err := collections.WatchShortcuts()
+	if err != nil {
+		log.Fatalf("could not load shortcuts: %v", err)
+	}
+	defer watcher.Close()
+
+	handler, err := graphql.NewHandler(cfg)
+	if err != nil {
+		log.Fatalf("could not create graphql handler: %v", err)
+	}
+
+	http.Handle("/graphql", handler)
+
+	log.Infof("listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, nil); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,127 @@
+// Command imx-proxy serves the Immutable X assets, collections and orders
+// endpoints locally, caching responses and sending all upstream requests
+// through one rate limiter so that a fleet of services has a single egress
+// point.
+//
+//	imx-proxy -addr :8080 -ttl-orders 10s
+//	curl localhost:8080/v1/collections/hero
+//	curl 'localhost:8080/v1/assets?collection=hero&page_size=50'
+//
+// Upstream requests use the SDK when -alchemy-key or IMX_ALCHEMY_KEY is set and
+// the public REST API otherwise. The shortcuts file in IMX_SHORTCUT_LOCATION is
+// reloaded when it changes. -metrics-addr serves Prometheus metrics for the
+// upstream requests and the cache.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/metrics"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/deadloct/immutablex-go-lib/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+func main() {
+	var (
+		addr        = flag.String("addr", ":8080", "address to listen on")
+		alchemyKey  = flag.String("alchemy-key", os.Getenv("IMX_ALCHEMY_KEY"), "Alchemy API key, uses the public REST API when empty")
+		rps         = flag.Float64("rps", utils.DefaultRequestsPerSecond, "upstream requests per second")
+		burst       = flag.Int("burst", utils.DefaultRequestBurst, "upstream request burst")
+		timeout     = flag.Duration("timeout", 30*time.Second, "upstream request timeout")
+		debug       = flag.Bool("debug", false, "enable debug logging")
+		metricsAddr = flag.String("metrics-addr", "", "address to serve Prometheus metrics on, disabled when empty")
+		ttls        = make(map[string]*time.Duration, len(defaultTTLs))
+	)
+
+	for endpoint, ttl := range defaultTTLs {
+		ttls[endpoint] = flag.Duration("ttl-"+endpoint, ttl, "cache duration for "+endpoint+" responses, 0 to disable")
+	}
+
+	flag.Parse()
+
+	if *debug {
+		log.SetLevel(log.DebugLevel)
+	}
+
+	utils.RateLimiter.SetLimit(rate.Limit(*rps))
+	utils.RateLimiter.SetBurst(*burst)
+
+	// Every upstream request waits for the limiter, including each page of a
+	// list the clients fetch on their own.
+	httpClient := utils.NewRateLimitedClient()
+	assetsClient := assets.NewClient(assets.NewClientConfigWithHTTPClient(*alchemyKey, httpClient))
+	collectionsClient := collections.NewClient(collections.NewClientConfigWithHTTPClient(*alchemyKey, httpClient))
+	ordersClient := orders.NewClient(orders.NewClientConfigWithHTTPClient(*alchemyKey, httpClient))
+
+	for _, c := range []interface {
+		Start() error
+		Stop()
+	}{assetsClient, collectionsClient, ordersClient} {
+		if err := c.Start(); err != nil {
+			log.Fatalf("could not start client: %v", err)
+		}
+		defer c.Stop()
+	}
+
+	s := newServer(assetsClient, collectionsClient, ordersClient)
+	s.timeout = *timeout
+	for endpoint, ttl := range ttls {
+		s.ttls[endpoint] = *ttl
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	collections.LoadShortcuts(ctx)
+
+	watcher, err := collections.WatchShortcuts()
+	if err != nil {
+		log.Fatalf("could not load shortcuts: %v", err)
+	}
+	defer watcher.Close()
+
+	unsubscribe := collections.SubscribeShortcuts(func(collections.Shortcuts) {
+		log.Infof("shortcuts reloaded, clearing cache")
+		s.clearCache()
+	})
+	defer unsubscribe()
+
+	if *metricsAddr != "" {
+		rec := metrics.NewPrometheusRecorder("imx")
+		prometheus.MustRegister(rec)
+		metrics.SetRecorder(rec)
+
+		go func() {
+			log.Infof("serving metrics on %s", *metricsAddr)
+			if err := http.ListenAndServe(*metricsAddr, promhttp.Handler()); err != nil {
+				log.Errorf("metrics server stopped: %v", err)
+			}
+		}()
+	}
+
+	httpServer := &http.Server{Addr: *addr, Handler: s}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	log.Infof("listening on %s", *addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
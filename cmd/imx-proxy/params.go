@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/orders"
+)
+
+func parseBool(q url.Values, key string) (bool, error) {
+	v := q.Get(key)
+	if v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, badRequestError{err: err}
+	}
+
+	return b, nil
+}
+
+func parseInt(q url.Values, key string) (int, error) {
+	v := q.Get(key)
+	if v == "" {
+		return 0, nil
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, badRequestError{err: err}
+	}
+
+	return i, nil
+}
+
+// defaultPageSize is the API's own page size, used when page_size is missing
+// so a bare list request doesn't fetch a whole collection.
+const defaultPageSize = 100
+
+func parsePageSize(q url.Values) (int, error) {
+	size, err := parseInt(q, "page_size")
+	if err != nil {
+		return 0, err
+	}
+
+	if size < 0 {
+		return 0, badRequestError{err: fmt.Errorf("page_size must not be negative")}
+	}
+
+	if size == 0 {
+		return defaultPageSize, nil
+	}
+
+	return size, nil
+}
+
+func parseListAssetsConfig(q url.Values) (*assets.ListAssetsConfig, error) {
+	cfg := &assets.ListAssetsConfig{
+		Collection:          q.Get("collection"),
+		Cursor:              q.Get("cursor"),
+		Direction:           q.Get("direction"),
+		Metadata:            q.Get("metadata"),
+		Name:                q.Get("name"),
+		OrderBy:             q.Get("order_by"),
+		Status:              q.Get("status"),
+		UpdatedMaxTimestamp: q.Get("updated_max_timestamp"),
+		UpdatedMinTimestamp: q.Get("updated_min_timestamp"),
+		User:                q.Get("user"),
+	}
+
+	var err error
+	if cfg.BuyOrders, err = parseBool(q, "buy_orders"); err != nil {
+		return nil, err
+	}
+
+	if cfg.IncludeFees, err = parseBool(q, "include_fees"); err != nil {
+		return nil, err
+	}
+
+	if cfg.SellOrders, err = parseBool(q, "sell_orders"); err != nil {
+		return nil, err
+	}
+
+	if cfg.PageSize, err = parsePageSize(q); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func parseListCollectionsConfig(q url.Values) (*collections.ListCollectionsConfig, error) {
+	cfg := &collections.ListCollectionsConfig{
+		Blacklist: q.Get("blacklist"),
+		Cursor:    q.Get("cursor"),
+		Direction: q.Get("direction"),
+		Keyword:   q.Get("keyword"),
+		OrderBy:   q.Get("order_by"),
+		Whitelist: q.Get("whitelist"),
+	}
+
+	var err error
+	if cfg.PageSize, err = parsePageSize(q); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func parseListOrdersConfig(q url.Values) (*orders.ListOrdersConfig, error) {
+	cfg := &orders.ListOrdersConfig{
+		AuxiliaryFeePercentages: q.Get("auxiliary_fee_percentages"),
+		AuxiliaryFeeRecipients:  q.Get("auxiliary_fee_recipients"),
+		BuyAssetID:              q.Get("buy_asset_id"),
+		BuyMaxQuantity:          q.Get("buy_max_quantity"),
+		BuyMetadata:             q.Get("buy_metadata"),
+		BuyMinQuantity:          q.Get("buy_min_quantity"),
+		BuyTokenAddress:         q.Get("buy_token_address"),
+		BuyTokenID:              q.Get("buy_token_id"),
+		BuyTokenName:            q.Get("buy_token_name"),
+		BuyTokenType:            q.Get("buy_token_type"),
+		Cursor:                  q.Get("cursor"),
+		Direction:               q.Get("direction"),
+		MaxTimestamp:            q.Get("max_timestamp"),
+		MinTimestamp:            q.Get("min_timestamp"),
+		OrderBy:                 q.Get("order_by"),
+		SellAssetID:             q.Get("sell_asset_id"),
+		SellMaxQuantity:         q.Get("sell_max_quantity"),
+		SellMetadata:            q.Get("sell_metadata"),
+		SellMinQuantity:         q.Get("sell_min_quantity"),
+		SellTokenAddress:        q.Get("sell_token_address"),
+		SellTokenID:             q.Get("sell_token_id"),
+		SellTokenName:           q.Get("sell_token_name"),
+		SellTokenType:           q.Get("sell_token_type"),
+		Status:                  q.Get("status"),
+		UpdatedMaxTimestamp:     q.Get("updated_max_timestamp"),
+		UpdatedMinTimestamp:     q.Get("updated_min_timestamp"),
+		User:                    q.Get("user"),
+	}
+
+	var err error
+	if cfg.IncludeFees, err = parseBool(q, "include_fees"); err != nil {
+		return nil, err
+	}
+
+	if cfg.PageSize, err = parsePageSize(q); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
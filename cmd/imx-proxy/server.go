@@ -0,0 +1,311 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/cache"
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/metrics"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/deadloct/immutablex-go-lib/utils"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	endpointAsset       = "asset"
+	endpointAssets      = "assets"
+	endpointCollection  = "collection"
+	endpointCollections = "collections"
+	endpointOrder       = "order"
+	endpointOrders      = "orders"
+
+	maxCacheEntries = 10000
+)
+
+var defaultTTLs = map[string]time.Duration{
+	endpointAsset:       time.Minute,
+	endpointAssets:      30 * time.Second,
+	endpointCollection:  10 * time.Minute,
+	endpointCollections: 5 * time.Minute,
+	endpointOrder:       10 * time.Second,
+	endpointOrders:      10 * time.Second,
+}
+
+type errorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type fetchFunc func(ctx context.Context, r *http.Request, path []string) (interface{}, error)
+
+type server struct {
+	assets      assets.Client
+	collections collections.Client
+	orders      orders.Client
+
+	cache   cache.Backend
+	group   singleflight.Group
+	ttls    map[string]time.Duration
+	timeout time.Duration
+
+	mux *http.ServeMux
+}
+
+func newServer(a assets.Client, c collections.Client, o orders.Client) *server {
+	s := &server{
+		assets:      a,
+		collections: c,
+		orders:      o,
+		cache:       cache.NewMemoryBackend(maxCacheEntries),
+		ttls:        make(map[string]time.Duration, len(defaultTTLs)),
+		timeout:     30 * time.Second,
+		mux:         http.NewServeMux(),
+	}
+
+	for endpoint, ttl := range defaultTTLs {
+		s.ttls[endpoint] = ttl
+	}
+
+	s.route(assets.ListAssetsEndpoint, endpointAssets, endpointAsset, s.listAssets, s.getAsset, 2, true)
+	s.route(collections.ListCollectionsEndpoint, endpointCollections, endpointCollection, s.listCollections, s.getCollection, 1, true)
+	s.route(orders.ListOrdersEndpoint, endpointOrders, endpointOrder, s.listOrders, s.getOrder, 1, false)
+
+	return s
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// route registers the list handler on prefix and the get handler on
+// prefix/{segments...}. When collectionPath is set, the first segment is a
+// collection address that may be given as a shortcut.
+func (s *server) route(prefix, listEndpoint, getEndpoint string, list, get fetchFunc, segments int, collectionPath bool) {
+	s.mux.Handle(prefix, s.handle(listEndpoint, list, 0, false))
+	s.mux.Handle(prefix+"/", http.StripPrefix(prefix+"/", s.handle(getEndpoint, get, segments, collectionPath)))
+}
+
+// handle serves fetch through the cache. Identical requests that arrive while
+// one is in flight share its upstream call. Handlers with path segments expect
+// exactly that many parts in the (prefix stripped) path.
+func (s *server) handle(endpoint string, fetch fetchFunc, segments int, collectionPath bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "only GET is supported")
+			return
+		}
+
+		var path []string
+		if segments > 0 {
+			path = resolvePath(r.URL.Path, collectionPath)
+			if len(path) != segments {
+				writeError(w, http.StatusNotFound, "not_found", "unknown path")
+				return
+			}
+		}
+
+		key := endpoint + ":" + strings.Join(path, "/") + "?" + r.URL.Query().Encode()
+
+		if body, ok, _ := s.cache.Get(r.Context(), key); ok {
+			log.Debugf("cache hit for %s", key)
+			metrics.GetRecorder().CacheHit(metrics.EndpointProxy)
+			writeBody(w, body, "HIT")
+			return
+		}
+		metrics.GetRecorder().CacheMiss(metrics.EndpointProxy)
+
+		v, err, shared := s.group.Do(key, func() (interface{}, error) {
+			// The upstream call outlives any single client that triggered it.
+			ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+			defer cancel()
+
+			result, err := fetch(ctx, r, path)
+			if err != nil {
+				return nil, err
+			}
+
+			body, err := json.Marshal(result)
+			if err != nil {
+				return nil, err
+			}
+
+			if ttl := s.ttls[endpoint]; ttl > 0 {
+				s.cache.Set(ctx, key, body, ttl)
+			}
+
+			return body, nil
+		})
+
+		if err != nil {
+			var badRequest badRequestError
+			if errors.As(err, &badRequest) {
+				writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+				return
+			}
+
+			log.Errorf("upstream request for %s failed: %v", key, err)
+			writeUpstreamError(w, err)
+			return
+		}
+
+		status := "MISS"
+		if shared {
+			status = "SHARED"
+		}
+
+		writeBody(w, v.([]byte), status)
+	})
+}
+
+// resolvePath splits the path and, when the first segment is a collection,
+// replaces a shortcut there with its address. Other segments such as order
+// and token IDs are left alone.
+func resolvePath(path string, collectionPath bool) []string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if collectionPath {
+		parts[0] = collections.ResolveCollection("path", parts[0])
+	}
+
+	return parts
+}
+
+// clearCache drops every cached response, e.g. when shortcuts change what a
+// path refers to.
+func (s *server) clearCache() {
+	if err := s.cache.DeletePrefix(context.Background(), ""); err != nil {
+		log.Errorf("could not clear cache: %v", err)
+	}
+}
+
+func (s *server) listAssets(ctx context.Context, r *http.Request, _ []string) (interface{}, error) {
+	cfg, err := parseListAssetsConfig(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := assets.ListAssetsPage(ctx, s.assets, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.ListAssetsResponse{Result: result, Cursor: cfg.Cursor, Remaining: remaining(len(result), cfg.PageSize)}, nil
+}
+
+func (s *server) getAsset(ctx context.Context, r *http.Request, path []string) (interface{}, error) {
+	includeFees, err := parseBool(r.URL.Query(), "include_fees")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.assets.GetAsset(ctx, path[0], path[1], includeFees)
+}
+
+func (s *server) listCollections(ctx context.Context, r *http.Request, _ []string) (interface{}, error) {
+	cfg, err := parseListCollectionsConfig(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.collections.ListCollections(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.ListCollectionsResponse{Result: result, Cursor: cfg.Cursor, Remaining: remaining(len(result), cfg.PageSize)}, nil
+}
+
+func (s *server) getCollection(ctx context.Context, r *http.Request, path []string) (interface{}, error) {
+	return s.collections.GetCollection(ctx, path[0])
+}
+
+func (s *server) listOrders(ctx context.Context, r *http.Request, _ []string) (interface{}, error) {
+	cfg, err := parseListOrdersConfig(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.orders.ListOrders(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.ListOrdersResponse{Result: result, Cursor: cfg.Cursor, Remaining: remaining(len(result), cfg.PageSize)}, nil
+}
+
+func (s *server) getOrder(ctx context.Context, r *http.Request, path []string) (interface{}, error) {
+	includeFees, err := parseBool(r.URL.Query(), "include_fees")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.orders.GetOrder(ctx, path[0], includeFees)
+}
+
+// remaining approximates the API's remaining flag: the clients fetch every page
+// unless a page size is set, in which case a full page may have more after it.
+func remaining(count, pageSize int) int32 {
+	if pageSize > 0 && count >= pageSize {
+		return 1
+	}
+
+	return 0
+}
+
+func writeBody(w http.ResponseWriter, body []byte, cacheStatus string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Cache", cacheStatus)
+	w.Write(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(errorResponse{Code: code, Message: message}); err != nil {
+		log.Errorf("could not write error response: %v", err)
+	}
+}
+
+// writeUpstreamError passes API errors through with their status and code,
+// so a missing asset is a 404 rather than a 502.
+func writeUpstreamError(w http.ResponseWriter, err error) {
+	var apiErr *utils.APIError
+	switch {
+	case errors.As(err, &apiErr):
+		code := apiErr.Code
+		if code == "" {
+			code = "upstream_error"
+		}
+
+		message := apiErr.Message
+		if message == "" {
+			message = http.StatusText(apiErr.StatusCode)
+		}
+
+		writeError(w, apiErr.StatusCode, code, message)
+	case errors.Is(err, context.DeadlineExceeded):
+		writeError(w, http.StatusGatewayTimeout, "upstream_timeout", err.Error())
+	default:
+		writeError(w, http.StatusBadGateway, "upstream_error", err.Error())
+	}
+}
+
+type badRequestError struct {
+	err error
+}
+
+func (e badRequestError) Error() string {
+	return fmt.Sprintf("invalid request: %v", e.err)
+}
+
+func (e badRequestError) Unwrap() error {
+	return e.err
+}
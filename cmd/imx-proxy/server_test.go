@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/imxtest"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+const heroAddress = "0x6465ef3009f3c474774f4afb607a5d600ea71d95"
+
+type fakes struct {
+	assets      *imxtest.AssetsClient
+	collections *imxtest.CollectionsClient
+	orders      *imxtest.OrdersClient
+}
+
+func newTestServer() (*server, fakes) {
+	f := imxtest.DefaultFixtures()
+	c := fakes{
+		assets:      imxtest.NewAssetsClient(f),
+		collections: imxtest.NewCollectionsClient(f),
+		orders:      imxtest.NewOrdersClient(f),
+	}
+
+	return newServer(c.assets, c.collections, c.orders), c
+}
+
+func get(s http.Handler, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+// blockingOrders holds GetOrder calls until release is closed.
+type blockingOrders struct {
+	orders.Client
+	release chan struct{}
+}
+
+func (c blockingOrders) GetOrder(ctx context.Context, orderID string, includeFees bool) (*api.Order, error) {
+	<-c.release
+	return c.Client.GetOrder(ctx, orderID, includeFees)
+}
+
+func TestConcurrentRequestsShareUpstreamCall(t *testing.T) {
+	s, c := newTestServer()
+	s.orders = blockingOrders{Client: c.orders, release: make(chan struct{})}
+	s.ttls[endpointOrder] = 0
+
+	var wg sync.WaitGroup
+	statuses := make([]string, 5)
+	for i := range statuses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rec := get(s, "/v3/orders/1")
+			statuses[i] = rec.Header().Get("X-Cache")
+			if rec.Code != http.StatusOK {
+				t.Errorf("got status %d", rec.Code)
+			}
+		}(i)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	close(s.orders.(blockingOrders).release)
+	wg.Wait()
+
+	if n := len(c.orders.CallsTo("GetOrder")); n != 1 {
+		t.Errorf("GetOrder called %d times for concurrent requests, want 1", n)
+	}
+
+	// Every request that took part in a shared call is marked as such,
+	// including the one that made it.
+	for _, status := range statuses {
+		if status != "SHARED" {
+			t.Errorf("got cache statuses %v, want SHARED", statuses)
+			break
+		}
+	}
+}
+
+func TestTTLsPerEndpoint(t *testing.T) {
+	s, c := newTestServer()
+	s.ttls[endpointCollection] = 50 * time.Millisecond
+	s.ttls[endpointOrder] = 0
+
+	for i, want := range []string{"MISS", "HIT"} {
+		if got := get(s, "/v1/collections/"+heroAddress).Header().Get("X-Cache"); got != want {
+			t.Errorf("collection request %d: got %s, want %s", i, got, want)
+		}
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if got := get(s, "/v1/collections/"+heroAddress).Header().Get("X-Cache"); got != "MISS" {
+		t.Errorf("got %s after the TTL, want MISS", got)
+	}
+
+	// A zero TTL disables caching for the endpoint.
+	for i := 0; i < 2; i++ {
+		if got := get(s, "/v3/orders/1").Header().Get("X-Cache"); got != "MISS" {
+			t.Errorf("order request %d: got %s, want MISS", i, got)
+		}
+	}
+
+	if n := len(c.orders.CallsTo("GetOrder")); n != 2 {
+		t.Errorf("GetOrder called %d times, want 2", n)
+	}
+}
+
+func TestShortcutPaths(t *testing.T) {
+	s, c := newTestServer()
+
+	if rec := get(s, "/v1/assets/hero/1"); rec.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", rec.Code, rec.Body)
+	}
+
+	// The shortcut and its address share a cache entry.
+	if got := get(s, "/v1/assets/"+heroAddress+"/1").Header().Get("X-Cache"); got != "HIT" {
+		t.Errorf("got %s for the address of a cached shortcut, want HIT", got)
+	}
+
+	calls := c.assets.CallsTo("GetAsset")
+	if len(calls) != 1 || calls[0].Args[0] != heroAddress {
+		t.Errorf("got GetAsset calls %v, want one for %s", calls, heroAddress)
+	}
+
+	get(s, "/v1/collections/hero")
+	if calls := c.collections.CallsTo("GetCollection"); len(calls) != 1 || calls[0].Args[0] != heroAddress {
+		t.Errorf("got GetCollection calls %v, want one for %s", calls, heroAddress)
+	}
+
+	// Order IDs aren't shortcuts.
+	get(s, "/v3/orders/hero")
+	if calls := c.orders.CallsTo("GetOrder"); len(calls) != 1 || calls[0].Args[0] != "hero" {
+		t.Errorf("got GetOrder calls %v, want one for hero", calls)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s, _ := newTestServer()
+
+	tests := []struct {
+		method, target string
+		status         int
+		code           string
+	}{
+		{http.MethodGet, "/v1/assets/hero/99", http.StatusNotFound, "not_found"},
+		{http.MethodGet, "/v3/orders/99", http.StatusNotFound, "not_found"},
+		{http.MethodGet, "/v1/assets/hero", http.StatusNotFound, "not_found"},
+		{http.MethodGet, "/v1/assets?page_size=-1", http.StatusBadRequest, "bad_request"},
+		{http.MethodGet, "/v3/orders?include_fees=maybe", http.StatusBadRequest, "bad_request"},
+		{http.MethodPost, "/v3/orders", http.StatusMethodNotAllowed, "method_not_allowed"},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+
+		var resp errorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s %s: %v", tt.method, tt.target, err)
+		}
+
+		if rec.Code != tt.status || resp.Code != tt.code {
+			t.Errorf("%s %s: got %d %s, want %d %s", tt.method, tt.target, rec.Code, resp.Code, tt.status, tt.code)
+		}
+	}
+}
+
+func TestUpstreamErrorsArentCached(t *testing.T) {
+	s, c := newTestServer()
+	c.collections.FailNext("GetCollection", &resetError{})
+
+	if rec := get(s, "/v1/collections/hero"); rec.Code != http.StatusBadGateway {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusBadGateway)
+	}
+
+	if rec := get(s, "/v1/collections/hero"); rec.Code != http.StatusOK || rec.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("got %d %s after a failure, want 200 MISS", rec.Code, rec.Header().Get("X-Cache"))
+	}
+}
+
+type resetError struct{}
+
+func (*resetError) Error() string { return "connection reset" }
+
+func TestListPages(t *testing.T) {
+	s, _ := newTestServer()
+
+	var resp api.ListAssetsResponse
+	rec := get(s, "/v1/assets?collection=hero&page_size=3")
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Result) != 3 || resp.Cursor == "" || resp.Remaining != 1 {
+		t.Fatalf("got %d assets, cursor %q and remaining %d", len(resp.Result), resp.Cursor, resp.Remaining)
+	}
+
+	rec = get(s, "/v1/assets?collection=hero&page_size=3&cursor="+resp.Cursor)
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Result) != 1 || resp.Remaining != 0 {
+		t.Errorf("got %d assets and remaining %d on the last page", len(resp.Result), resp.Remaining)
+	}
+}
+
+func TestClearCache(t *testing.T) {
+	s, _ := newTestServer()
+
+	get(s, "/v1/collections/hero")
+	s.clearCache()
+
+	if got := get(s, "/v1/collections/hero").Header().Get("X-Cache"); got != "MISS" {
+		t.Errorf("got %s after clearing the cache, want MISS", got)
+	}
+}
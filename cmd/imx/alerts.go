@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/deadloct/immutablex-go-lib/alerts"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/output"
+)
+
+var alertFields = []string{"rule", "order_id", "price", "currency", "fiat_price", "user", "immutascan_url"}
+
+func setupAlerts(fs *flag.FlagSet) runFunc {
+	rulesFile := fs.String("rules", "", "rule file (YAML or JSON)")
+	locale := fs.String("locale", coinbase.DefaultLocale, "locale used to format fiat prices")
+
+	return func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error {
+		if *rulesFile == "" {
+			fs.Usage()
+			return errors.New("-rules is required")
+		}
+
+		rules, err := alerts.LoadFile(*rulesFile)
+		if err != nil {
+			return err
+		}
+
+		client, err := startOrdersClient(opts)
+		if err != nil {
+			return err
+		}
+		defer client.Stop()
+
+		matches, err := alerts.NewEngine(alerts.Config{Rules: rules}).Check(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		items := make([]interface{}, len(matches))
+		for i, m := range matches {
+			fiatPrice := ""
+			if m.Rule.Fiat != "" {
+				fiatPrice = coinbase.FormatFiat(m.FiatPrice, m.Rule.Fiat, *locale)
+			}
+
+			items[i] = map[string]interface{}{
+				"rule":           m.Rule.Name,
+				"order_id":       m.Order.OrderId,
+				"price":          m.Price,
+				"currency":       m.Currency,
+				"fiat_price":     fiatPrice,
+				"user":           m.Order.User,
+				"immutascan_url": output.Immutascan("order", m.Order.OrderId),
+			}
+		}
+
+		if output.IsStandard(opts.output) {
+			for _, m := range matches {
+				line := fmt.Sprintf("%s: %s", m.Rule.Name, output.FormatPrice(m.Price, m.Currency))
+				if m.Rule.Fiat != "" {
+					line += " (" + coinbase.FormatFiat(m.FiatPrice, m.Rule.Fiat, *locale) + ")"
+				}
+				fmt.Printf("%s %s\n", line, output.Immutascan("order", m.Order.OrderId))
+			}
+			return nil
+		}
+
+		return output.Write(os.Stdout, opts.output, false, items, alertFields, nil)
+	}
+}
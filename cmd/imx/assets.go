@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+)
+
+func startAssetsClient(opts *commonOptions) (assets.Client, error) {
+	client := assets.NewClient(assets.NewClientConfig(opts.alchemyKey))
+	if err := client.Start(); err != nil {
+		return nil, fmt.Errorf("could not start assets client: %w", err)
+	}
+
+	return client, nil
+}
+
+func setupAssetsList(fs *flag.FlagSet) runFunc {
+	var cfg assets.ListAssetsConfig
+	fs.BoolVar(&cfg.BuyOrders, "buy-orders", false, "include buy orders")
+	fs.StringVar(&cfg.Collection, "collection", "", "collection address or shortcut")
+	fs.StringVar(&cfg.Direction, "direction", "", "sort direction (asc, desc)")
+	fs.BoolVar(&cfg.IncludeFees, "fees", false, "include fees")
+	fs.StringVar(&cfg.Metadata, "metadata", "", "URL JSON-encoded metadata filters")
+	fs.StringVar(&cfg.Name, "name", "", "asset name")
+	fs.StringVar(&cfg.OrderBy, "order-by", "", "property to sort by")
+	fs.IntVar(&cfg.PageSize, "limit", 0, "maximum number of assets to return, 0 for all")
+	fs.BoolVar(&cfg.SellOrders, "sell-orders", false, "include sell orders")
+	fs.StringVar(&cfg.Status, "status", "", "asset status")
+	fs.StringVar(&cfg.UpdatedMaxTimestamp, "updated-max", "", "maximum updated timestamp (RFC 3339)")
+	fs.StringVar(&cfg.UpdatedMinTimestamp, "updated-min", "", "minimum updated timestamp (RFC 3339)")
+	fs.StringVar(&cfg.User, "user", "", "owner address")
+
+	return func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error {
+		client, err := startAssetsClient(opts)
+		if err != nil {
+			return err
+		}
+		defer client.Stop()
+
+		result, err := client.ListAssets(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		return assets.WriteAssets(os.Stdout, cfg.Collection, result, opts.output)
+	}
+}
+
+func setupAssetsGet(fs *flag.FlagSet) runFunc {
+	includeFees := fs.Bool("fees", false, "include fees")
+
+	return func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error {
+		if len(args) != 2 {
+			fs.Usage()
+			return flag.ErrHelp
+		}
+
+		client, err := startAssetsClient(opts)
+		if err != nil {
+			return err
+		}
+		defer client.Stop()
+
+		asset, err := client.GetAsset(ctx, args[0], args[1], *includeFees)
+		if err != nil {
+			return err
+		}
+
+		return assets.WriteAsset(os.Stdout, asset.TokenAddress, asset, opts.output)
+	}
+}
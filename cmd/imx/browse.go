@@ -0,0 +1,403 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+const clearScreen = "\033[H\033[2J"
+
+var errQuit = errors.New("quit")
+
+// browser is a small interactive terminal UI over the collections, assets and
+// orders clients. Each view is redrawn after every command typed at its prompt.
+type browser struct {
+	ctx      context.Context
+	in       *bufio.Scanner
+	out      io.Writer
+	pageSize int
+	fiat     coinbase.FiatSymbol
+	locale   string
+
+	collections collections.Client
+	assets      assets.Client
+	orders      orders.Client
+}
+
+func setupBrowse(fs *flag.FlagSet) runFunc {
+	pageSize := fs.Int("page-size", 20, "number of items loaded at a time")
+	keyword := fs.String("keyword", "", "only list collections matching keyword")
+	fiatOpts := addFiatFlags(fs)
+
+	return func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error {
+		fiat, err := coinbase.NewFiatSymbol(fiatOpts.fiat)
+		if err != nil {
+			return err
+		}
+
+		collectionsClient, err := startCollectionsClient(opts)
+		if err != nil {
+			return err
+		}
+		defer collectionsClient.Stop()
+
+		assetsClient, err := startAssetsClient(opts)
+		if err != nil {
+			return err
+		}
+		defer assetsClient.Stop()
+
+		ordersClient, err := startOrdersClient(opts)
+		if err != nil {
+			return err
+		}
+		defer ordersClient.Stop()
+
+		b := &browser{
+			ctx:         ctx,
+			in:          bufio.NewScanner(os.Stdin),
+			out:         os.Stdout,
+			pageSize:    *pageSize,
+			fiat:        fiat,
+			locale:      fiatOpts.locale,
+			collections: collectionsClient,
+			assets:      assetsClient,
+			orders:      ordersClient,
+		}
+
+		if err := b.browseCollections(*keyword); err != nil && !errors.Is(err, errQuit) {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// prompt reads a command, returning errQuit on "q" or end of input.
+func (b *browser) prompt(help string) (string, string, error) {
+	fmt.Fprintf(b.out, "\n%s\n> ", help)
+	if !b.in.Scan() {
+		return "", "", errQuit
+	}
+
+	line := strings.TrimSpace(b.in.Text())
+	cmd, arg := line, ""
+	if i := strings.IndexByte(line, ' '); i >= 0 {
+		cmd, arg = line[:i], strings.TrimSpace(line[i+1:])
+	}
+
+	if cmd == "q" {
+		return "", "", errQuit
+	}
+
+	return cmd, arg, b.ctx.Err()
+}
+
+func (b *browser) status(format string, args ...interface{}) {
+	fmt.Fprintf(b.out, format+"\n", args...)
+}
+
+func (b *browser) browseCollections(keyword string) error {
+	var (
+		cfg    = collections.ListCollectionsConfig{Keyword: keyword, PageSize: b.pageSize}
+		items  []api.Collection
+		filter string
+		more   = true
+		msg    string
+	)
+
+	load := func() error {
+		cfg.Collections = nil
+		page, err := b.collections.ListCollections(b.ctx, &cfg)
+		if err != nil {
+			return err
+		}
+
+		items = append(items, page...)
+		more = len(page) == b.pageSize
+		return nil
+	}
+
+	if err := load(); err != nil {
+		return err
+	}
+
+	for {
+		visible := filterCollections(items, filter)
+
+		fmt.Fprint(b.out, clearScreen)
+		b.status("Collections (%d loaded%s)", len(items), describeFilter(filter))
+		for i, c := range visible {
+			b.status("%4d  %-40s  %s", i+1, c.Name, c.Address)
+		}
+		if msg != "" {
+			b.status("\n%s", msg)
+			msg = ""
+		}
+
+		cmd, arg, err := b.prompt("[number] open  [m] more  [f text] filter by name  [s keyword] search  [q] quit")
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case cmd == "m":
+			if !more {
+				msg = "no more collections"
+				continue
+			}
+			if err := load(); err != nil {
+				msg = err.Error()
+			}
+		case cmd == "f":
+			filter = arg
+		case cmd == "s":
+			cfg = collections.ListCollectionsConfig{Keyword: arg, PageSize: b.pageSize}
+			items, filter = nil, ""
+			if err := load(); err != nil {
+				msg = err.Error()
+			}
+		case isIndex(cmd, len(visible)):
+			i, _ := strconv.Atoi(cmd)
+			if err := b.browseAssets(visible[i-1]); err != nil {
+				if errors.Is(err, errQuit) {
+					return err
+				}
+				msg = err.Error()
+			}
+		case cmd != "":
+			msg = fmt.Sprintf("unknown command %q", cmd)
+		}
+	}
+}
+
+func (b *browser) browseAssets(collection api.Collection) error {
+	var (
+		cfg   assets.ListAssetsConfig
+		items []api.AssetWithOrders
+		more  = true
+		msg   string
+	)
+
+	reset := func(status, name, metadata string) {
+		cfg = assets.ListAssetsConfig{
+			Collection: collection.Address,
+			PageSize:   b.pageSize,
+			Status:     status,
+			Name:       name,
+			Metadata:   metadata,
+		}
+		items, more = nil, true
+	}
+
+	load := func() error {
+		cfg.Assets = nil
+		page, err := assets.ListAssetsPage(b.ctx, b.assets, &cfg)
+		if err != nil {
+			return err
+		}
+
+		items = append(items, page...)
+		more = len(page) == b.pageSize
+		return nil
+	}
+
+	reset("", "", "")
+	if err := load(); err != nil {
+		return err
+	}
+
+	for {
+		fmt.Fprint(b.out, clearScreen)
+		b.status("%s: assets (%d loaded%s)", collection.Name, len(items), describeAssetFilters(cfg))
+		for i, a := range items {
+			b.status("%4d  %-10s  %-40s  %s", i+1, a.TokenId, a.GetName(), a.Status)
+		}
+		if msg != "" {
+			b.status("\n%s", msg)
+			msg = ""
+		}
+
+		cmd, arg, err := b.prompt("[number] open  [m] more  [n name] [s status] [meta key=value] filter  [c] clear filters  [b] back  [q] quit")
+		if err != nil {
+			return err
+		}
+
+		reload := false
+		switch {
+		case cmd == "b":
+			return nil
+		case cmd == "m":
+			if !more {
+				msg = "no more assets"
+				continue
+			}
+			if err := load(); err != nil {
+				msg = err.Error()
+			}
+		case cmd == "n":
+			reset(cfg.Status, arg, cfg.Metadata)
+			reload = true
+		case cmd == "s":
+			reset(arg, cfg.Name, cfg.Metadata)
+			reload = true
+		case cmd == "meta":
+			metadata, err := metadataFilter(arg)
+			if err != nil {
+				msg = err.Error()
+				continue
+			}
+			reset(cfg.Status, cfg.Name, metadata)
+			reload = true
+		case cmd == "c":
+			reset("", "", "")
+			reload = true
+		case isIndex(cmd, len(items)):
+			i, _ := strconv.Atoi(cmd)
+			if err := b.showAsset(collection, items[i-1]); err != nil {
+				if errors.Is(err, errQuit) {
+					return err
+				}
+				msg = err.Error()
+			}
+		case cmd != "":
+			msg = fmt.Sprintf("unknown command %q", cmd)
+		}
+
+		if reload {
+			if err := load(); err != nil {
+				msg = err.Error()
+			}
+		}
+	}
+}
+
+func (b *browser) showAsset(collection api.Collection, asset api.AssetWithOrders) error {
+	cfg := orders.ListOrdersConfig{
+		SellTokenAddress: collection.Address,
+		SellTokenID:      asset.TokenId,
+		Status:           "active",
+		IncludeFees:      true,
+	}
+
+	active, err := b.orders.ListOrders(b.ctx, &cfg)
+	if err != nil {
+		return err
+	}
+
+	for {
+		fmt.Fprint(b.out, clearScreen)
+		b.status("%s #%s", collection.Name, asset.TokenId)
+		if err := assets.WriteAssetWithOrdersStandard(b.out, collection.Address, &asset); err != nil {
+			return err
+		}
+
+		b.status("\nOwner: %s\nMetadata:", asset.User)
+		keys := make([]string, 0, len(asset.Metadata))
+		for k := range asset.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			b.status("  %s: %v", k, asset.Metadata[k])
+		}
+
+		b.status("\nActive orders (%d):", len(active))
+		if err := orders.WriteOrders(b.out, active, "table=order_id,price,currency,fiat_price,user,updated_timestamp", b.fiat, b.locale); err != nil {
+			return err
+		}
+
+		cmd, _, err := b.prompt("[b] back  [q] quit")
+		if err != nil {
+			return err
+		}
+
+		if cmd == "b" {
+			return nil
+		}
+	}
+}
+
+func filterCollections(items []api.Collection, filter string) []api.Collection {
+	if filter == "" {
+		return items
+	}
+
+	var result []api.Collection
+	for _, c := range items {
+		if strings.Contains(strings.ToLower(c.Name), strings.ToLower(filter)) {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+// metadataFilter converts key=value into the URL JSON-encoded metadata filter
+// the API expects, e.g. {"rarity":["Legendary"]}.
+func metadataFilter(arg string) (string, error) {
+	if arg == "" {
+		return "", nil
+	}
+
+	parts := strings.SplitN(arg, "=", 2)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("metadata filter must be key=value")
+	}
+
+	data, err := json.Marshal(map[string][]string{parts[0]: {parts[1]}})
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func describeFilter(filter string) string {
+	if filter == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(", filter %q", filter)
+}
+
+func describeAssetFilters(cfg assets.ListAssetsConfig) string {
+	var parts []string
+	if cfg.Name != "" {
+		parts = append(parts, "name="+cfg.Name)
+	}
+
+	if cfg.Status != "" {
+		parts = append(parts, "status="+cfg.Status)
+	}
+
+	if cfg.Metadata != "" {
+		parts = append(parts, "metadata="+cfg.Metadata)
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return ", " + strings.Join(parts, " ")
+}
+
+func isIndex(cmd string, n int) bool {
+	i, err := strconv.Atoi(cmd)
+	return err == nil && i >= 1 && i <= n
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/deadloct/immutablex-go-lib/collections"
+)
+
+func startCollectionsClient(opts *commonOptions) (collections.Client, error) {
+	client := collections.NewClient(collections.NewClientConfig(opts.alchemyKey))
+	if err := client.Start(); err != nil {
+		return nil, fmt.Errorf("could not start collections client: %w", err)
+	}
+
+	return client, nil
+}
+
+func setupCollectionsList(fs *flag.FlagSet) runFunc {
+	var cfg collections.ListCollectionsConfig
+	fs.StringVar(&cfg.Blacklist, "blacklist", "", "comma separated collection addresses or shortcuts to exclude")
+	fs.StringVar(&cfg.Direction, "direction", "", "sort direction (asc, desc)")
+	fs.StringVar(&cfg.Keyword, "keyword", "", "keyword to search in collection name and description")
+	fs.StringVar(&cfg.OrderBy, "order-by", "", "property to sort by")
+	fs.IntVar(&cfg.PageSize, "limit", 0, "maximum number of collections to return, 0 for all")
+	fs.StringVar(&cfg.Whitelist, "whitelist", "", "comma separated collection addresses or shortcuts to include")
+
+	return func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error {
+		client, err := startCollectionsClient(opts)
+		if err != nil {
+			return err
+		}
+		defer client.Stop()
+
+		result, err := client.ListCollections(ctx, &cfg)
+		if err != nil {
+			return err
+		}
+
+		return collections.WriteCollections(os.Stdout, result, opts.output)
+	}
+}
+
+func setupCollectionsGet(fs *flag.FlagSet) runFunc {
+	return func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error {
+		if len(args) != 1 {
+			fs.Usage()
+			return flag.ErrHelp
+		}
+
+		client, err := startCollectionsClient(opts)
+		if err != nil {
+			return err
+		}
+		defer client.Stop()
+
+		collection, err := client.GetCollection(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		return collections.WriteCollection(os.Stdout, collection, opts.output)
+	}
+}
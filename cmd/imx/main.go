@@ -0,0 +1,170 @@
+// Command imx queries Immutable X assets, collections, orders and prices.
+//
+// Usage:
+//
+//	imx assets list -collection hero -status imx
+//	imx assets get hero 1234
+//	imx collections list -keyword bitverse -o table
+//	imx collections get hero
+//	imx orders list -sell-token-address hero -status active -fiat EUR -locale de-DE
+//	imx orders get 123456
+//	imx price ETH IMX
+//	imx browse -keyword bitverse
+//	imx alerts -rules alerts.yaml
+//	imx shortcuts generate -file shortcuts.json -keyword bitverse
+//	imx shortcuts add -file shortcuts.json gods 0xacb3c6a43d15b907e8433077b6d38ae40936fe2c "Gods Unchained"
+//
+// When an Alchemy API key is set with -alchemy-key or IMX_ALCHEMY_KEY the SDK
+// backend is used, otherwise requests go to the public REST API.
+//
+// Shortcuts are merged from the built-in defaults, $XDG_CONFIG_HOME/imx/shortcuts.json,
+// the nearest .imx/shortcuts.json, IMX_SHORTCUT_URL and IMX_SHORTCUT_LOCATION,
+// later sources overriding earlier ones; "imx shortcuts sources" shows where
+// each comes from.
+//
+// Collection and token address arguments accept shortcuts, and user arguments
+// accept wallet aliases from the JSON file in IMX_WALLET_ALIAS_LOCATION, e.g.
+// {"treasury": "0x..."}.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/output"
+	log "github.com/sirupsen/logrus"
+)
+
+type commonOptions struct {
+	alchemyKey string
+	output     string
+	debug      bool
+}
+
+type runFunc func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error
+
+type command struct {
+	usage string
+	setup func(fs *flag.FlagSet) runFunc
+}
+
+var commands = map[string]map[string]command{
+	"assets": {
+		"list": {usage: "assets list [flags]", setup: setupAssetsList},
+		"get":  {usage: "assets get [flags] <collection> <token-id>", setup: setupAssetsGet},
+	},
+	"collections": {
+		"list": {usage: "collections list [flags]", setup: setupCollectionsList},
+		"get":  {usage: "collections get [flags] <collection>", setup: setupCollectionsGet},
+	},
+	"orders": {
+		"list": {usage: "orders list [flags]", setup: setupOrdersList},
+		"get":  {usage: "orders get [flags] <order-id>", setup: setupOrdersGet},
+	},
+	"alerts": {
+		"": {usage: "alerts [flags] -rules <file>", setup: setupAlerts},
+	},
+	"price": {
+		"": {usage: "price [flags] <crypto>...", setup: setupPrice},
+	},
+	"shortcuts": {
+		"list":     {usage: "shortcuts list [flags]", setup: setupShortcutsList},
+		"add":      {usage: "shortcuts add [flags] <shortcut> <address> <name>", setup: setupShortcutsAdd},
+		"remove":   {usage: "shortcuts remove [flags] <shortcut>", setup: setupShortcutsRemove},
+		"rename":   {usage: "shortcuts rename [flags] <old> <new>", setup: setupShortcutsRename},
+		"generate": {usage: "shortcuts generate [flags] -keyword <keyword>", setup: setupShortcutsGenerate},
+		"sources":  {usage: "shortcuts sources [flags]", setup: setupShortcutsSources},
+	},
+	"browse": {
+		"": {usage: "browse [flags]", setup: setupBrowse},
+	},
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		usage()
+		return flag.ErrHelp
+	}
+
+	subcommands, ok := commands[args[0]]
+	if !ok {
+		usage()
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	name, rest := args[0], args[1:]
+	cmd, ok := subcommands[""]
+	if !ok {
+		if len(rest) == 0 {
+			usage()
+			return fmt.Errorf("%s requires a subcommand", name)
+		}
+
+		if cmd, ok = subcommands[rest[0]]; !ok {
+			usage()
+			return fmt.Errorf("unknown command %q", name+" "+rest[0])
+		}
+
+		name, rest = name+" "+rest[0], rest[1:]
+	}
+
+	var opts commonOptions
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: imx %s\n\nFlags:\n", cmd.usage)
+		fs.PrintDefaults()
+	}
+	addCommonFlags(fs, &opts)
+	runCmd := cmd.setup(fs)
+
+	if err := fs.Parse(rest); err != nil {
+		return err
+	}
+
+	if opts.debug {
+		log.SetLevel(log.DebugLevel)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	collections.LoadShortcuts(ctx)
+
+	return runCmd(ctx, &opts, fs, fs.Args())
+}
+
+func addCommonFlags(fs *flag.FlagSet, opts *commonOptions) {
+	formats := strings.Join(append([]string{"standard"}, output.Names()...), ", ")
+
+	fs.StringVar(&opts.alchemyKey, "alchemy-key", os.Getenv("IMX_ALCHEMY_KEY"), "Alchemy API key, uses the public REST API when empty")
+	fs.StringVar(&opts.output, "o", "standard", "output format ("+formats+"), e.g. csv=name,token_id or template={{.name}}")
+	fs.BoolVar(&opts.debug, "debug", false, "enable debug logging")
+}
+
+func usage() {
+	var lines []string
+	for _, subcommands := range commands {
+		for _, cmd := range subcommands {
+			lines = append(lines, "  imx "+cmd.usage)
+		}
+	}
+	sort.Strings(lines)
+
+	fmt.Fprintf(os.Stderr, "Usage:\n%s\n\nRun a command with -h for its flags.\n", strings.Join(lines, "\n"))
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+type fiatOptions struct {
+	fiat   string
+	locale string
+}
+
+func addFiatFlags(fs *flag.FlagSet) *fiatOptions {
+	var opts fiatOptions
+	fs.StringVar(&opts.fiat, "fiat", string(coinbase.FiatUSD), "ISO-4217 currency for fiat prices")
+	fs.StringVar(&opts.locale, "locale", coinbase.DefaultLocale, "locale used to format fiat prices")
+	return &opts
+}
+
+func startOrdersClient(opts *commonOptions) (orders.Client, error) {
+	client := orders.NewClient(orders.NewClientConfig(opts.alchemyKey))
+	if err := client.Start(); err != nil {
+		return nil, fmt.Errorf("could not start orders client: %w", err)
+	}
+
+	return client, nil
+}
+
+func writeOrders(opts *commonOptions, fiatOpts *fiatOptions, result []api.Order) error {
+	fiat, err := coinbase.NewFiatSymbol(fiatOpts.fiat)
+	if err != nil {
+		return err
+	}
+
+	return orders.WriteOrders(os.Stdout, result, opts.output, fiat, fiatOpts.locale)
+}
+
+func setupOrdersList(fs *flag.FlagSet) runFunc {
+	var cfg orders.ListOrdersConfig
+	fs.StringVar(&cfg.AuxiliaryFeePercentages, "auxiliary-fee-percentages", "", "comma separated auxiliary fee percentages")
+	fs.StringVar(&cfg.AuxiliaryFeeRecipients, "auxiliary-fee-recipients", "", "comma separated auxiliary fee recipients")
+	fs.StringVar(&cfg.BuyAssetID, "buy-asset-id", "", "internal IMX ID of the asset being bought")
+	fs.StringVar(&cfg.BuyMaxQuantity, "buy-max-quantity", "", "maximum quantity of the asset being bought")
+	fs.StringVar(&cfg.BuyMetadata, "buy-metadata", "", "URL JSON-encoded metadata filters for the asset being bought")
+	fs.StringVar(&cfg.BuyMinQuantity, "buy-min-quantity", "", "minimum quantity of the asset being bought")
+	fs.StringVar(&cfg.BuyTokenAddress, "buy-token-address", "", "token address or shortcut of the asset being bought")
+	fs.StringVar(&cfg.BuyTokenID, "buy-token-id", "", "token ID of the asset being bought")
+	fs.StringVar(&cfg.BuyTokenName, "buy-token-name", "", "token name of the asset being bought")
+	fs.StringVar(&cfg.BuyTokenType, "buy-token-type", "", "token type of the asset being bought")
+	fs.StringVar(&cfg.Direction, "direction", "", "sort direction (asc, desc)")
+	fs.BoolVar(&cfg.IncludeFees, "fees", false, "include fees")
+	fs.StringVar(&cfg.MaxTimestamp, "max-timestamp", "", "maximum created timestamp (RFC 3339)")
+	fs.StringVar(&cfg.MinTimestamp, "min-timestamp", "", "minimum created timestamp (RFC 3339)")
+	fs.StringVar(&cfg.OrderBy, "order-by", "", "property to sort by")
+	fs.IntVar(&cfg.PageSize, "limit", 0, "maximum number of orders to return, 0 for all")
+	fs.StringVar(&cfg.SellAssetID, "sell-asset-id", "", "internal IMX ID of the asset being sold")
+	fs.StringVar(&cfg.SellMaxQuantity, "sell-max-quantity", "", "maximum quantity of the asset being sold")
+	fs.StringVar(&cfg.SellMetadata, "sell-metadata", "", "URL JSON-encoded metadata filters for the asset being sold")
+	fs.StringVar(&cfg.SellMinQuantity, "sell-min-quantity", "", "minimum quantity of the asset being sold")
+	fs.StringVar(&cfg.SellTokenAddress, "sell-token-address", "", "token address or shortcut of the asset being sold")
+	fs.StringVar(&cfg.SellTokenID, "sell-token-id", "", "token ID of the asset being sold")
+	fs.StringVar(&cfg.SellTokenName, "sell-token-name", "", "token name of the asset being sold")
+	fs.StringVar(&cfg.SellTokenType, "sell-token-type", "", "token type of the asset being sold")
+	fs.StringVar(&cfg.Status, "status", "", "order status (active, filled, cancelled, expired, inactive)")
+	fs.StringVar(&cfg.UpdatedMaxTimestamp, "updated-max", "", "maximum updated timestamp (RFC 3339)")
+	fs.StringVar(&cfg.UpdatedMinTimestamp, "updated-min", "", "minimum updated timestamp (RFC 3339)")
+	fs.StringVar(&cfg.User, "user", "", "ethereum address of the user who submitted the order")
+	fiatOpts := addFiatFlags(fs)
+
+	return func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error {
+		client, err := startOrdersClient(opts)
+		if err != nil {
+			return err
+		}
+		defer client.Stop()
+
+		result, err := client.ListOrders(ctx, &cfg)
+		if err != nil {
+			return err
+		}
+
+		return writeOrders(opts, fiatOpts, result)
+	}
+}
+
+func setupOrdersGet(fs *flag.FlagSet) runFunc {
+	includeFees := fs.Bool("fees", true, "include fees")
+	fiatOpts := addFiatFlags(fs)
+
+	return func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error {
+		if len(args) != 1 {
+			fs.Usage()
+			return flag.ErrHelp
+		}
+
+		client, err := startOrdersClient(opts)
+		if err != nil {
+			return err
+		}
+		defer client.Stop()
+
+		order, err := client.GetOrder(ctx, args[0], *includeFees)
+		if err != nil {
+			return err
+		}
+
+		return writeOrders(opts, fiatOpts, []api.Order{*order})
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/output"
+)
+
+var priceFields = []string{"crypto", "fiat", "price", "formatted"}
+
+func setupPrice(fs *flag.FlagSet) runFunc {
+	fiatOpts := addFiatFlags(fs)
+
+	return func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error {
+		if len(args) == 0 {
+			args = []string{string(coinbase.CryptoETH), string(coinbase.CryptoIMX), string(coinbase.CryptoUSDC)}
+		}
+
+		fiat, err := coinbase.NewFiatSymbol(fiatOpts.fiat)
+		if err != nil {
+			return err
+		}
+
+		pairs := make([]coinbase.SpotPair, len(args))
+		for i, arg := range args {
+			pairs[i] = coinbase.SpotPair{Crypto: coinbase.CryptoSymbol(strings.ToUpper(arg)), Fiat: fiat}
+		}
+
+		prices := coinbase.GetPriceClient().RetrieveSpotPrices(ctx, pairs)
+
+		items := make([]interface{}, len(pairs))
+		for i, pair := range pairs {
+			price := prices[pair]
+			items[i] = map[string]interface{}{
+				"crypto":    string(pair.Crypto),
+				"fiat":      string(pair.Fiat),
+				"price":     price,
+				"formatted": coinbase.FormatFiat(price, pair.Fiat, fiatOpts.locale),
+			}
+		}
+
+		if output.IsStandard(opts.output) {
+			for _, pair := range pairs {
+				fmt.Printf("%s: %s\n", pair.Crypto, coinbase.FormatFiat(prices[pair], pair.Fiat, fiatOpts.locale))
+			}
+			return nil
+		}
+
+		return output.Write(os.Stdout, opts.output, len(items) == 1, items, priceFields, nil)
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/output"
+)
+
+var (
+	shortcutFields       = []string{"shortcut", "name", "addr"}
+	shortcutSourceFields = []string{"shortcut", "addr", "source", "location", "overrides"}
+)
+
+func addShortcutsFileFlag(fs *flag.FlagSet) *string {
+	return fs.String("file", collections.ShortcutLocation, "shortcuts file, defaults to IMX_SHORTCUT_LOCATION")
+}
+
+func loadRegistry(path string) (*collections.ShortcutRegistry, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%w, use -file or IMX_SHORTCUT_LOCATION", collections.ErrNoShortcutsFile)
+	}
+
+	return collections.LoadShortcutRegistry(path)
+}
+
+func setupShortcutsList(fs *flag.FlagSet) runFunc {
+	file := addShortcutsFileFlag(fs)
+
+	return func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error {
+		var list []collections.Shortcut
+		if *file == "" {
+			// Without a file, list what the clients use.
+			for _, e := range collections.LoadLayeredShortcuts(ctx).List() {
+				list = append(list, e.Shortcut)
+			}
+		} else {
+			registry, err := loadRegistry(*file)
+			if err != nil {
+				return err
+			}
+			list = registry.List()
+		}
+
+		if output.IsStandard(opts.output) {
+			for _, s := range list {
+				fmt.Printf("%-12s %s  %s\n", s.Shortcut, s.Addr, s.Name)
+			}
+			return nil
+		}
+
+		items := make([]interface{}, len(list))
+		for i, s := range list {
+			items[i] = s
+		}
+
+		return output.Write(os.Stdout, opts.output, false, items, shortcutFields, nil)
+	}
+}
+
+func setupShortcutsAdd(fs *flag.FlagSet) runFunc {
+	file := addShortcutsFileFlag(fs)
+
+	return func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error {
+		if len(args) != 3 {
+			fs.Usage()
+			return fmt.Errorf("expected shortcut, address and name")
+		}
+
+		registry, err := loadRegistry(*file)
+		if err != nil {
+			return err
+		}
+
+		if err := registry.Add(collections.Shortcut{Shortcut: args[0], Addr: args[1], Name: args[2]}); err != nil {
+			return err
+		}
+
+		return registry.Save()
+	}
+}
+
+func setupShortcutsRemove(fs *flag.FlagSet) runFunc {
+	file := addShortcutsFileFlag(fs)
+
+	return func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error {
+		if len(args) != 1 {
+			fs.Usage()
+			return fmt.Errorf("expected a shortcut")
+		}
+
+		registry, err := loadRegistry(*file)
+		if err != nil {
+			return err
+		}
+
+		if err := registry.Remove(args[0]); err != nil {
+			return err
+		}
+
+		return registry.Save()
+	}
+}
+
+func setupShortcutsRename(fs *flag.FlagSet) runFunc {
+	file := addShortcutsFileFlag(fs)
+
+	return func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error {
+		if len(args) != 2 {
+			fs.Usage()
+			return fmt.Errorf("expected the old and new shortcut")
+		}
+
+		registry, err := loadRegistry(*file)
+		if err != nil {
+			return err
+		}
+
+		if err := registry.Rename(args[0], args[1]); err != nil {
+			return err
+		}
+
+		return registry.Save()
+	}
+}
+
+func setupShortcutsGenerate(fs *flag.FlagSet) runFunc {
+	file := addShortcutsFileFlag(fs)
+	keyword := fs.String("keyword", "", "collection search keyword")
+	limit := fs.Int("limit", 20, "maximum number of collections to search")
+	pick := fs.String("pick", "", "comma separated results to add (e.g. 1,3) or \"all\", prompts when empty")
+
+	return func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error {
+		if *keyword == "" {
+			fs.Usage()
+			return fmt.Errorf("-keyword is required")
+		}
+
+		registry, err := loadRegistry(*file)
+		if err != nil {
+			return err
+		}
+
+		client, err := startCollectionsClient(opts)
+		if err != nil {
+			return err
+		}
+		defer client.Stop()
+
+		proposed, err := collections.GenerateShortcuts(ctx, client, registry, *keyword, *limit)
+		if err != nil {
+			return err
+		}
+
+		if len(proposed) == 0 {
+			fmt.Println("no new collections found")
+			return nil
+		}
+
+		for i, s := range proposed {
+			fmt.Printf("%3d  %-24s %s  %s\n", i+1, s.Shortcut, s.Addr, s.Name)
+		}
+
+		selection := *pick
+		if selection == "" {
+			fmt.Print("\nadd which (e.g. 1,3 or all, empty to cancel)? ")
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				return err
+			}
+			selection = strings.TrimSpace(line)
+		}
+
+		chosen, err := parseSelection(selection, len(proposed))
+		if err != nil {
+			return err
+		}
+
+		for _, i := range chosen {
+			if err := registry.Add(proposed[i]); err != nil {
+				return err
+			}
+			fmt.Printf("added %s\n", proposed[i].Shortcut)
+		}
+
+		if len(chosen) == 0 {
+			return nil
+		}
+
+		return registry.Save()
+	}
+}
+
+// parseSelection converts "1,3" or "all" into zero based indexes.
+func parseSelection(selection string, n int) ([]int, error) {
+	if selection == "" {
+		return nil, nil
+	}
+
+	var chosen []int
+	if selection == "all" {
+		for i := 0; i < n; i++ {
+			chosen = append(chosen, i)
+		}
+		return chosen, nil
+	}
+
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(selection, ",") {
+		part = strings.TrimSpace(part)
+		if !isIndex(part, n) {
+			return nil, fmt.Errorf("invalid selection %q", part)
+		}
+
+		i, _ := strconv.Atoi(part)
+		if !seen[i] {
+			seen[i] = true
+			chosen = append(chosen, i-1)
+		}
+	}
+
+	return chosen, nil
+}
+
+func setupShortcutsSources(fs *flag.FlagSet) runFunc {
+	return func(ctx context.Context, opts *commonOptions, fs *flag.FlagSet, args []string) error {
+		layered := collections.LoadLayeredShortcuts(ctx)
+		for _, layer := range layered.Layers {
+			if layer.Err != nil {
+				fmt.Fprintf(os.Stderr, "warning: skipped %s shortcuts %s: %v\n", layer.Source, layer.Location, layer.Err)
+			}
+		}
+
+		list := layered.List()
+		if output.IsStandard(opts.output) {
+			for _, e := range list {
+				line := fmt.Sprintf("%-12s %s  %-8s %s", e.Shortcut.Shortcut, e.Addr, e.Source, e.Location)
+				if len(e.Overrides) > 0 {
+					line += " (overrides " + strings.Join(e.Overrides, ", ") + ")"
+				}
+				fmt.Println(strings.TrimSpace(line))
+			}
+			return nil
+		}
+
+		items := make([]interface{}, len(list))
+		for i, e := range list {
+			items[i] = e
+		}
+
+		return output.Write(os.Stdout, opts.output, false, items, shortcutSourceFields, nil)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		selection string
+		want      []int
+	}{
+		{"", nil},
+		{"all", []int{0, 1, 2}},
+		{"1", []int{0}},
+		{"3, 1", []int{2, 0}},
+		{"2,2,1", []int{1, 0}},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSelection(tt.selection, 3)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSelection(%q) = %v, %v, want %v", tt.selection, got, err, tt.want)
+		}
+	}
+
+	for _, selection := range []string{"0", "4", "1,x", "-1", "1,,2", "ALL", "1-2"} {
+		if _, err := parseSelection(selection, 3); err == nil {
+			t.Errorf("parseSelection(%q) should fail", selection)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package coinbase
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -8,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/deadloct/immutablex-go-lib/metrics"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -19,6 +21,10 @@ const (
 	BaseURL  = "https://api.coinbase.com"
 	CacheFor = 30 * time.Second
 
+	// MaxConcurrentFetches limits the number of spot prices requested at once
+	// by RetrieveSpotPrices.
+	MaxConcurrentFetches = 4
+
 	CryptoETH  CryptoSymbol = "ETH"
 	CryptoIMX  CryptoSymbol = "IMX"
 	CryptoUSDC CryptoSymbol = "USDC"
@@ -30,9 +36,16 @@ const (
 
 var (
 	coinbaseClientInstance *CoinbaseClient
+	priceClient            PriceClient
 	muCoinbase             sync.Mutex
 )
 
+// PriceClient retrieves spot prices. *CoinbaseClient implements it.
+type PriceClient interface {
+	RetrieveSpotPrice(crypto CryptoSymbol, fiat FiatSymbol) float64
+	RetrieveSpotPrices(ctx context.Context, pairs []SpotPair) map[SpotPair]float64
+}
+
 type SupportedCurrencies string
 
 type CoinbaseSpotPriceReponse struct {
@@ -50,9 +63,25 @@ type Price struct {
 	LastRetrieved time.Time
 }
 
+type SpotPair struct {
+	Crypto CryptoSymbol
+	Fiat   FiatSymbol
+}
+
+type CoinbaseClientConfig struct {
+	// BaseURL defaults to the public Coinbase API.
+	BaseURL string
+
+	// HTTPClient sends every request, a new http.Client is used when nil.
+	HTTPClient *http.Client
+}
+
 type CoinbaseClient struct {
 	client         *http.Client
+	baseURL        string
 	lastSpotPrices map[string]Price
+
+	sync.Mutex
 }
 
 func GetCoinbaseClientInstance() *CoinbaseClient {
@@ -60,54 +89,192 @@ func GetCoinbaseClientInstance() *CoinbaseClient {
 	defer muCoinbase.Unlock()
 
 	if coinbaseClientInstance == nil {
-		coinbaseClientInstance = &CoinbaseClient{
-			client:         &http.Client{},
-			lastSpotPrices: make(map[string]Price),
-		}
+		coinbaseClientInstance = NewCoinbaseClient(CoinbaseClientConfig{})
 	}
 
 	return coinbaseClientInstance
 }
 
-func (c *CoinbaseClient) RetrieveSpotPrice(crypto CryptoSymbol, fiat FiatSymbol) float64 {
-	if fiat == "" {
-		fiat = FiatUSD
+// GetPriceClient returns the client set with SetPriceClient, or the shared
+// Coinbase client. The library looks prices up through it.
+func GetPriceClient() PriceClient {
+	muCoinbase.Lock()
+	c := priceClient
+	muCoinbase.Unlock()
+
+	if c != nil {
+		return c
 	}
 
-	if crypto == "" {
-		crypto = CryptoETH
+	return GetCoinbaseClientInstance()
+}
+
+// SetPriceClient replaces the client returned by GetPriceClient for the whole
+// process. nil restores the shared Coinbase client. Prefer the Prices field of
+// the components that have one, such as alerts.Config, graphql.Config and
+// notify.Renderer, which doesn't affect other users.
+func SetPriceClient(c PriceClient) {
+	muCoinbase.Lock()
+	defer muCoinbase.Unlock()
+
+	priceClient = c
+}
+
+func NewCoinbaseClient(cfg CoinbaseClientConfig) *CoinbaseClient {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = BaseURL
+	}
+
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{}
+	}
+
+	return &CoinbaseClient{
+		client:         cfg.HTTPClient,
+		baseURL:        cfg.BaseURL,
+		lastSpotPrices: make(map[string]Price),
 	}
+}
 
-	spotKey := c.getSpotKey(fiat, crypto)
+// SetHTTPClient replaces the client spot prices are fetched with, e.g. to
+// record or replay them in tests.
+func (c *CoinbaseClient) SetHTTPClient(client *http.Client) {
+	c.Lock()
+	defer c.Unlock()
 
-	last, ok := c.lastSpotPrices[spotKey]
-	if ok && time.Since(last.LastRetrieved) <= CacheFor {
-		return c.lastSpotPrices[spotKey].Price
+	c.client = client
+}
+
+func (c *CoinbaseClient) RetrieveSpotPrice(crypto CryptoSymbol, fiat FiatSymbol) float64 {
+	pair := normalizePair(SpotPair{Crypto: crypto, Fiat: fiat})
+	if price, ok := c.getCachedPrice(pair); ok {
+		return price
 	}
 
-	resp, err := c.client.Get(fmt.Sprintf("%s/v2/prices/%s-%s/spot", BaseURL, crypto, fiat))
+	amount, err := c.fetchSpotPrice(context.Background(), pair)
 	if err != nil {
-		log.Errorf("all %s-%s prices will be zero b/c error retrieving spot price: %v", crypto, fiat, err)
+		log.Errorf("all %s-%s prices will be zero b/c %v", pair.Crypto, pair.Fiat, err)
 		return 0
 	}
+
+	return amount
+}
+
+// RetrieveSpotPrices fetches the spot prices of all pairs concurrently and
+// warms the cache, so that subsequent RetrieveSpotPrice calls for the same
+// pairs don't hit the network. Pairs that fail to load are returned as zero.
+func (c *CoinbaseClient) RetrieveSpotPrices(ctx context.Context, pairs []SpotPair) map[SpotPair]float64 {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		seen    = make(map[SpotPair]bool, len(pairs))
+		results = make(map[SpotPair]float64, len(pairs))
+		sem     = make(chan struct{}, MaxConcurrentFetches)
+	)
+
+	for _, pair := range pairs {
+		pair = normalizePair(pair)
+		if seen[pair] {
+			continue
+		}
+		seen[pair] = true
+
+		if price, ok := c.getCachedPrice(pair); ok {
+			mu.Lock()
+			results[pair] = price
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(pair SpotPair) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			amount, err := c.fetchSpotPrice(ctx, pair)
+			if err != nil {
+				log.Errorf("all %s-%s prices will be zero b/c %v", pair.Crypto, pair.Fiat, err)
+			}
+
+			mu.Lock()
+			results[pair] = amount
+			mu.Unlock()
+		}(pair)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func (c *CoinbaseClient) getCachedPrice(pair SpotPair) (float64, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	last, ok := c.lastSpotPrices[c.getSpotKey(pair.Fiat, pair.Crypto)]
+	if !ok || time.Since(last.LastRetrieved) > CacheFor {
+		return 0, false
+	}
+
+	return last.Price, true
+}
+
+func (c *CoinbaseClient) fetchSpotPrice(ctx context.Context, pair SpotPair) (amount float64, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.GetRecorder().CoinbaseFetch(fmt.Sprintf("%s-%s", pair.Crypto, pair.Fiat), time.Since(start), err)
+	}()
+
+	c.Lock()
+	client, baseURL := c.client, c.baseURL
+	c.Unlock()
+
+	url := fmt.Sprintf("%s/v2/prices/%s-%s/spot", baseURL, pair.Crypto, pair.Fiat)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error creating spot price request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving spot price: %w", err)
+	}
 	defer resp.Body.Close()
 
 	var result CoinbaseSpotPriceReponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		log.Errorf("all %s-%s prices will be zero b/c error parsing spot price: %v", crypto, fiat, err)
-		return 0
+		return 0, fmt.Errorf("error parsing spot price: %w", err)
 	}
 
-	amount, err := strconv.ParseFloat(result.Data.Amount, 64)
+	amount, err = strconv.ParseFloat(result.Data.Amount, 64)
 	if err != nil {
-		log.Errorf("all %s-%s prices will be zero b/c error parsing spot price: %v", crypto, fiat, err)
-		return 0
+		return 0, fmt.Errorf("error parsing spot price: %w", err)
 	}
 
-	c.lastSpotPrices[spotKey] = Price{Price: amount, LastRetrieved: time.Now()}
-	return amount
+	c.Lock()
+	c.lastSpotPrices[c.getSpotKey(pair.Fiat, pair.Crypto)] = Price{Price: amount, LastRetrieved: time.Now()}
+	c.Unlock()
+
+	return amount, nil
 }
 
 func (c *CoinbaseClient) getSpotKey(f FiatSymbol, cr CryptoSymbol) string {
 	return fmt.Sprintf("%s-%s", f, cr)
 }
+
+func normalizePair(pair SpotPair) SpotPair {
+	if pair.Fiat == "" {
+		pair.Fiat = FiatUSD
+	}
+
+	if pair.Crypto == "" {
+		pair.Crypto = CryptoETH
+	}
+
+	return pair
+}
@@ -0,0 +1,99 @@
+package coinbase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// spotServer serves a spot price of 2 for every pair, counting requests per
+// pair and the most requests in flight at once.
+type spotServer struct {
+	hits        map[string]int
+	inFlight    int
+	maxInFlight int
+
+	sync.Mutex
+}
+
+func (s *spotServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pair := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v2/prices/"), "/spot")
+
+	s.Lock()
+	s.hits[pair]++
+	s.inFlight++
+	if s.inFlight > s.maxInFlight {
+		s.maxInFlight = s.inFlight
+	}
+	s.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	s.Lock()
+	s.inFlight--
+	s.Unlock()
+
+	fmt.Fprintf(w, `{"data": {"base": "x", "currency": "y", "amount": "2"}}`)
+}
+
+func TestRetrieveSpotPrices(t *testing.T) {
+	s := &spotServer{hits: make(map[string]int)}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	c := NewCoinbaseClient(CoinbaseClientConfig{BaseURL: srv.URL})
+
+	var pairs []SpotPair
+	for _, fiat := range []FiatSymbol{FiatUSD, FiatEUR, FiatGBP, "CHF"} {
+		for _, crypto := range []CryptoSymbol{CryptoETH, CryptoIMX, CryptoUSDC} {
+			pair := SpotPair{Crypto: crypto, Fiat: fiat}
+			pairs = append(pairs, pair, pair)
+		}
+	}
+	// Empty fields default to ETH and USD.
+	pairs = append(pairs, SpotPair{})
+
+	results := c.RetrieveSpotPrices(context.Background(), pairs)
+	if len(results) != 12 {
+		t.Errorf("got %d results, want 12", len(results))
+	}
+
+	for pair, price := range results {
+		if price != 2 {
+			t.Errorf("%s-%s: got %v, want 2", pair.Crypto, pair.Fiat, price)
+		}
+	}
+
+	s.Lock()
+	for pair, n := range s.hits {
+		if n != 1 {
+			t.Errorf("%s fetched %d times, want once", pair, n)
+		}
+	}
+	if len(s.hits) != 12 {
+		t.Errorf("fetched %d pairs, want 12", len(s.hits))
+	}
+	if s.maxInFlight < 2 || s.maxInFlight > MaxConcurrentFetches {
+		t.Errorf("%d requests in flight, want between 2 and %d", s.maxInFlight, MaxConcurrentFetches)
+	}
+	s.Unlock()
+
+	// The cache is warm, so neither call hits the server.
+	if price := c.RetrieveSpotPrice(CryptoIMX, FiatEUR); price != 2 {
+		t.Errorf("got cached price %v, want 2", price)
+	}
+	c.RetrieveSpotPrices(context.Background(), pairs)
+
+	s.Lock()
+	defer s.Unlock()
+	for pair, n := range s.hits {
+		if n != 1 {
+			t.Errorf("%s fetched %d times after warming the cache", pair, n)
+		}
+	}
+}
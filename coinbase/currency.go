@@ -0,0 +1,168 @@
+package coinbase
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const DefaultLocale = "en-US"
+
+// Locale describes how amounts are written for a language/region.
+type Locale struct {
+	Decimal     string
+	Group       string
+	SymbolAfter bool
+}
+
+var locales = map[string]Locale{
+	"en-US": {Decimal: ".", Group: ","},
+	"en-GB": {Decimal: ".", Group: ","},
+	"en-IE": {Decimal: ".", Group: ","},
+	"en-CA": {Decimal: ".", Group: ","},
+	"en-AU": {Decimal: ".", Group: ","},
+	"ja-JP": {Decimal: ".", Group: ","},
+	"de-DE": {Decimal: ",", Group: ".", SymbolAfter: true},
+	"de-AT": {Decimal: ",", Group: ".", SymbolAfter: true},
+	"de-CH": {Decimal: ".", Group: "'", SymbolAfter: true},
+	"es-ES": {Decimal: ",", Group: ".", SymbolAfter: true},
+	"it-IT": {Decimal: ",", Group: ".", SymbolAfter: true},
+	"nl-NL": {Decimal: ",", Group: "."},
+	"pt-PT": {Decimal: ",", Group: " ", SymbolAfter: true},
+	"pt-BR": {Decimal: ",", Group: "."},
+	"fr-FR": {Decimal: ",", Group: " ", SymbolAfter: true},
+	"fr-BE": {Decimal: ",", Group: " ", SymbolAfter: true},
+	"pl-PL": {Decimal: ",", Group: " ", SymbolAfter: true},
+	"sv-SE": {Decimal: ",", Group: " ", SymbolAfter: true},
+}
+
+var languageDefaults = map[string]string{
+	"de": "de-DE",
+	"en": "en-US",
+	"es": "es-ES",
+	"fr": "fr-FR",
+	"it": "it-IT",
+	"ja": "ja-JP",
+	"nl": "nl-NL",
+	"pl": "pl-PL",
+	"pt": "pt-PT",
+	"sv": "sv-SE",
+}
+
+var currencySymbols = map[FiatSymbol]string{
+	FiatUSD: "$",
+	FiatEUR: "€",
+	FiatGBP: "£",
+	"AUD":   "A$",
+	"BRL":   "R$",
+	"CAD":   "CA$",
+	"CNY":   "CN¥",
+	"INR":   "₹",
+	"JPY":   "¥",
+	"KRW":   "₩",
+	"NZD":   "NZ$",
+	"PLN":   "zł",
+}
+
+// Currencies that don't use the default of two minor units.
+var currencyDecimals = map[FiatSymbol]int{
+	"JPY": 0,
+	"KRW": 0,
+	"CLP": 0,
+	"ISK": 0,
+	"BHD": 3,
+	"KWD": 3,
+	"OMR": 3,
+}
+
+// NewFiatSymbol validates an ISO-4217 currency code such as "usd" or "CHF" and
+// returns it as a FiatSymbol.
+func NewFiatSymbol(code string) (FiatSymbol, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", fmt.Errorf("invalid currency code %q: must be three letters", code)
+	}
+
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("invalid currency code %q: must be three letters", code)
+		}
+	}
+
+	return FiatSymbol(code), nil
+}
+
+// GetLocale returns the formatting rules for a locale tag like "de-DE" or
+// "fr_FR", falling back to the language alone and then to DefaultLocale.
+func GetLocale(tag string) Locale {
+	tag = strings.ReplaceAll(tag, "_", "-")
+	if l, ok := locales[tag]; ok {
+		return l
+	}
+
+	lang := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+	if l, ok := locales[languageDefaults[lang]]; ok {
+		return l
+	}
+
+	return locales[DefaultLocale]
+}
+
+// FormatFiat formats an amount in the given currency, e.g. "$1,234.56" for
+// en-US or "1.234,56 €" for de-DE. Currencies without a known symbol are
+// written with their code.
+func FormatFiat(amount float64, fiat FiatSymbol, locale string) string {
+	if fiat == "" {
+		fiat = FiatUSD
+	}
+
+	l := GetLocale(locale)
+	decimals, ok := currencyDecimals[fiat]
+	if !ok {
+		decimals = 2
+	}
+
+	sign := ""
+	if amount < 0 && math.Round(math.Abs(amount)*math.Pow10(decimals)) != 0 {
+		sign = "-"
+	}
+
+	number := formatNumber(math.Abs(amount), decimals, l)
+	symbol, ok := currencySymbols[fiat]
+	if !ok {
+		return sign + number + " " + string(fiat)
+	}
+
+	if l.SymbolAfter {
+		return sign + number + " " + symbol
+	}
+
+	return sign + symbol + number
+}
+
+func formatNumber(amount float64, decimals int, l Locale) string {
+	// Round halves away from zero as prices are, FormatFloat rounds them to
+	// even.
+	scale := math.Pow10(decimals)
+	s := strconv.FormatFloat(math.Round(amount*scale)/scale, 'f', decimals, 64)
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+
+	var b strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(l.Group)
+		}
+		b.WriteRune(r)
+	}
+
+	if fracPart != "" {
+		b.WriteString(l.Decimal)
+		b.WriteString(fracPart)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package coinbase
+
+import "testing"
+
+func TestFormatFiat(t *testing.T) {
+	tests := []struct {
+		amount float64
+		fiat   FiatSymbol
+		locale string
+		want   string
+	}{
+		{1234.56, FiatUSD, "en-US", "$1,234.56"},
+		{1234.56, FiatEUR, "de-DE", "1.234,56 €"},
+		{1234.56, FiatEUR, "fr-FR", "1 234,56 €"},
+		{1234.56, "CHF", "de-CH", "1'234.56 CHF"},
+		{1234.56, FiatGBP, "en-GB", "£1,234.56"},
+		{1234567.891, "BRL", "pt-BR", "R$1.234.567,89"},
+		{1234.5, "JPY", "ja-JP", "¥1,235"},
+		{1.2346, "KWD", "en-US", "1.235 KWD"},
+		{0.5, FiatUSD, "en-US", "$0.50"},
+		{999, FiatUSD, "en-US", "$999.00"},
+		{-1234.5, FiatUSD, "en-US", "-$1,234.50"},
+		{-0.001, FiatUSD, "en-US", "$0.00"},
+		{12, "", "", "$12.00"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatFiat(tt.amount, tt.fiat, tt.locale); got != tt.want {
+			t.Errorf("FormatFiat(%v, %q, %q) = %q, want %q", tt.amount, tt.fiat, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestGetLocale(t *testing.T) {
+	tests := map[string]Locale{
+		"de-DE":   locales["de-DE"],
+		"de_AT":   locales["de-AT"],
+		"fr":      locales["fr-FR"],
+		"fr-CA":   locales["fr-FR"],
+		"PT-xx":   locales["pt-PT"],
+		"":        locales[DefaultLocale],
+		"xx-YY":   locales[DefaultLocale],
+		"unknown": locales[DefaultLocale],
+	}
+
+	for tag, want := range tests {
+		if got := GetLocale(tag); got != want {
+			t.Errorf("GetLocale(%q) = %+v, want %+v", tag, got, want)
+		}
+	}
+}
+
+func TestLocaleTables(t *testing.T) {
+	for tag, l := range locales {
+		if l.Decimal == "" || l.Group == "" || l.Decimal == l.Group {
+			t.Errorf("%s: decimal %q and group %q must be set and differ", tag, l.Decimal, l.Group)
+		}
+	}
+
+	for lang, tag := range languageDefaults {
+		if _, ok := locales[tag]; !ok {
+			t.Errorf("default locale %s for %s is missing", tag, lang)
+		}
+	}
+}
+
+func TestNewFiatSymbol(t *testing.T) {
+	for code, want := range map[string]FiatSymbol{"usd": FiatUSD, " CHF ": "CHF"} {
+		got, err := NewFiatSymbol(code)
+		if err != nil || got != want {
+			t.Errorf("NewFiatSymbol(%q) = %q, %v, want %q", code, got, err, want)
+		}
+	}
+
+	for _, code := range []string{"", "US", "USDT", "U$D"} {
+		if _, err := NewFiatSymbol(code); err == nil {
+			t.Errorf("NewFiatSymbol(%q) should fail", code)
+		}
+	}
+}
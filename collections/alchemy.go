@@ -2,25 +2,37 @@ package collections
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	"github.com/deadloct/immutablex-go-lib/imx"
+	"github.com/deadloct/immutablex-go-lib/metrics"
 	"github.com/immutable/imx-core-sdk-golang/imx/api"
 	log "github.com/sirupsen/logrus"
 )
 
 type AlchemyClientConfig struct {
 	alchemyKey string
+
+	// HTTPClient is passed to the SDK, its default is used when nil.
+	HTTPClient *http.Client
+
+	// Client replaces the SDK client wrapper, e.g. with a fake in tests.
+	Client imx.ClientWrapper
 }
 
 type AlchemyClient struct {
-	client    imx.ClientWrapper
-	shortcuts Shortcuts
+	client imx.ClientWrapper
 }
 
 func NewAlchemyClient(cfg AlchemyClientConfig) *AlchemyClient {
+	var client imx.ClientWrapper = cfg.Client
+	if client == nil {
+		client = imx.NewClientWithHTTPClient(cfg.alchemyKey, cfg.HTTPClient)
+	}
+
 	return &AlchemyClient{
-		client:    imx.NewClient(cfg.alchemyKey),
-		shortcuts: NewShortcuts(),
+		client: client,
 	}
 }
 
@@ -33,22 +45,27 @@ func (c *AlchemyClient) Stop() {
 }
 
 func (c *AlchemyClient) GetCollection(ctx context.Context, collection string) (*api.Collection, error) {
-	if v := c.shortcuts.GetShortcutByName(collection); v != nil {
-		collection = v.Addr
-	}
+	collection = ResolveCollection("collection", collection)
 
 	log.Debugf("fetching collection %s", collection)
-	return c.client.GetClient().GetCollection(ctx, collection)
+	start := time.Now()
+	result, err := c.client.GetClient().GetCollection(metrics.WithEndpoint(ctx, metrics.EndpointGetCollection), collection)
+	metrics.ObserveCall(metrics.EndpointGetCollection, start, err)
+	return result, err
 }
 
 func (c *AlchemyClient) ListCollections(ctx context.Context, cfg *ListCollectionsConfig) ([]api.Collection, error) {
-	req := c.getAPIListCollectionsRequest(ctx, cfg)
+	req := c.getAPIListCollectionsRequest(metrics.WithEndpoint(ctx, metrics.EndpointListCollections), cfg)
 
+	start := time.Now()
 	resp, err := c.client.GetClient().ListCollections(req)
+	metrics.ObserveCall(metrics.EndpointListCollections, start, err)
 	if err != nil {
 		return nil, err
 	}
 
+	metrics.GetRecorder().Page(metrics.EndpointListCollections, len(resp.Result))
+
 	if len(resp.Result) == 0 {
 		return cfg.Collections, nil
 	}
@@ -56,11 +73,12 @@ func (c *AlchemyClient) ListCollections(ctx context.Context, cfg *ListCollection
 	cfg.Collections = append(cfg.Collections, resp.Result...)
 	cfg.Cursor = resp.Cursor
 
-	first := *resp.Result[0].UpdatedAt.Get()
-	last := *resp.Result[len(resp.Result)-1].UpdatedAt.Get()
+	first := resp.Result[0].GetUpdatedAt()
+	last := resp.Result[len(resp.Result)-1].GetUpdatedAt()
 	log.Debugf("fetched %v collections from %v to %v", len(resp.Result), first, last)
 
-	if resp.Remaining > 0 {
+	getMore := len(cfg.Collections) < cfg.PageSize || cfg.PageSize == 0
+	if resp.Remaining > 0 && getMore {
 		return c.ListCollections(ctx, cfg)
 	}
 
@@ -71,7 +89,7 @@ func (c *AlchemyClient) getAPIListCollectionsRequest(ctx context.Context, cfg *L
 	req := c.client.GetClient().NewListCollectionsRequest(ctx)
 
 	if cfg.Blacklist != "" {
-		req = req.Blacklist(cfg.Blacklist)
+		req = req.Blacklist(ResolveCollections("blacklist", cfg.Blacklist))
 	}
 
 	if cfg.Cursor != "" {
@@ -90,8 +108,12 @@ func (c *AlchemyClient) getAPIListCollectionsRequest(ctx context.Context, cfg *L
 		req = req.OrderBy(cfg.OrderBy)
 	}
 
+	if cfg.PageSize > 0 {
+		req = req.PageSize(int32(cfg.PageSize - len(cfg.Collections)))
+	}
+
 	if cfg.Whitelist != "" {
-		req = req.Whitelist(cfg.Whitelist)
+		req = req.Whitelist(ResolveCollections("whitelist", cfg.Whitelist))
 	}
 
 	return &req
@@ -2,6 +2,7 @@ package collections
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/deadloct/immutablex-go-lib/utils"
 	"github.com/immutable/imx-core-sdk-golang/imx/api"
@@ -13,9 +14,11 @@ type ListCollectionsConfig struct {
 	Direction string
 	Keyword   string
 	OrderBy   string
+	PageSize  int
 	Whitelist string
 
-	// Used internally for recursion
+	// Used internally for recursion. When PageSize is set, Cursor is left at
+	// the last page fetched so the next call continues from there.
 	Collections []api.Collection
 	Cursor      string
 }
@@ -28,11 +31,18 @@ type Client interface {
 }
 
 func NewClientConfig(alchemyKey string) interface{} {
+	return NewClientConfigWithHTTPClient(alchemyKey, nil)
+}
+
+// NewClientConfigWithHTTPClient is NewClientConfig with the HTTP client every
+// request is sent with, the default is used when nil. Wrap its transport in a
+// utils.RetryTransport to retry requests the API is too busy for.
+func NewClientConfigWithHTTPClient(alchemyKey string, httpClient *http.Client) interface{} {
 	if alchemyKey == "" {
-		return RESTClientConfig{url: utils.DefaultImmutableAPIURL}
+		return RESTClientConfig{URL: utils.DefaultImmutableAPIURL, HTTPClient: httpClient}
 	}
 
-	return AlchemyClientConfig{alchemyKey: alchemyKey}
+	return AlchemyClientConfig{alchemyKey: alchemyKey, HTTPClient: httpClient}
 }
 
 func NewClient(cfg interface{}) Client {
@@ -0,0 +1,75 @@
+package collections
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// GenerateShortcuts searches collections by keyword and proposes a shortcut for
+// each result whose address isn't already in the registry. Shortcuts are slugs
+// of the collection names, suffixed with -2, -3... when taken in the registry
+// or the current shortcuts.
+func GenerateShortcuts(ctx context.Context, client Client, registry *ShortcutRegistry, keyword string, limit int) ([]Shortcut, error) {
+	cfg := ListCollectionsConfig{Keyword: keyword, PageSize: limit}
+	result, err := client.ListCollections(ctx, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not search collections: %w", err)
+	}
+
+	taken := make(map[string]bool)
+	for key := range CurrentShortcuts() {
+		taken[strings.ToLower(key)] = true
+	}
+	for _, s := range registry.List() {
+		taken[strings.ToLower(s.Shortcut)] = true
+	}
+
+	var proposed []Shortcut
+	for _, c := range result {
+		if _, ok := registry.GetByAddress(c.Address); ok {
+			continue
+		}
+
+		name := c.Name
+		if name == "" {
+			name = c.Address
+		}
+
+		shortcut := UniqueSlug(Slugify(c.Name), taken)
+		taken[shortcut] = true
+		proposed = append(proposed, Shortcut{Name: name, Addr: c.Address, Shortcut: shortcut})
+	}
+
+	return proposed, nil
+}
+
+// Slugify lowercases a name and joins its letters and digits with hyphens,
+// e.g. "BitVerse Heroes" becomes "bitverse-heroes".
+func Slugify(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+
+	slug := strings.Join(words, "-")
+	if slug == "" || IsAddress(slug) {
+		return "collection"
+	}
+
+	return slug
+}
+
+// UniqueSlug returns slug, or slug with the lowest numeric suffix not in taken.
+func UniqueSlug(slug string, taken map[string]bool) string {
+	if !taken[slug] {
+		return slug
+	}
+
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", slug, i)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
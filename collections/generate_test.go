@@ -0,0 +1,82 @@
+package collections_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/imxtest"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"BitVerse Heroes":     "bitverse-heroes",
+		"  Gods Unchained!! ": "gods-unchained",
+		"Cross_The--Ages 2":   "cross-the-ages-2",
+		"":                    "collection",
+		"***":                 "collection",
+		"日本":                  "collection",
+		"0x6465ef3009f3c474774f4afb607a5d600ea71d95": "collection",
+	}
+
+	for name, want := range tests {
+		if got := collections.Slugify(name); got != want {
+			t.Errorf("Slugify(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestUniqueSlug(t *testing.T) {
+	taken := map[string]bool{"hero": true, "hero-2": true, "hero-4": true}
+
+	tests := map[string]string{"hero": "hero-3", "portal": "portal", "hero-2": "hero-2-2"}
+	for slug, want := range tests {
+		if got := collections.UniqueSlug(slug, taken); got != want {
+			t.Errorf("UniqueSlug(%q) = %q, want %q", slug, got, want)
+		}
+	}
+}
+
+func TestGenerateShortcuts(t *testing.T) {
+	prev := collections.CurrentShortcuts()
+	defer collections.SetShortcuts(prev)
+
+	collections.SetShortcuts(collections.Shortcuts{
+		"bitverse-heroes": {Name: "BitVerse Heroes", Addr: "0x1234567890123456789012345678901234567890", Shortcut: "bitverse-heroes"},
+	})
+
+	var extra api.Collection
+	if err := json.Unmarshal([]byte(`{"name": "BitVerse Heroes", "address": "0xabcdefabcdefabcdefabcdefabcdefabcdefabcd"}`), &extra); err != nil {
+		t.Fatal(err)
+	}
+
+	client := imxtest.NewCollectionsClient(imxtest.DefaultFixtures())
+	client.AddCollections(extra)
+
+	registry, err := collections.NewShortcutRegistry("", []collections.Shortcut{
+		{Name: "Portals", Addr: "0xe4ac52f4b4a721d1d0ad8c9c689df401c2db7291", Shortcut: "bitverse-heroes-2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proposed, err := collections.GenerateShortcuts(context.Background(), client, registry, "bitverse", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Portals is already registered, and the heroes slugs avoid the current
+	// shortcuts, the registry and each other.
+	want := []string{"bitverse-heroes-3", "bitverse-heroes-4"}
+	if len(proposed) != len(want) {
+		t.Fatalf("got %d proposals %v, want %v", len(proposed), proposed, want)
+	}
+
+	for i, s := range proposed {
+		if s.Shortcut != want[i] {
+			t.Errorf("proposal %d is %s, want %s", i, s.Shortcut, want[i])
+		}
+	}
+}
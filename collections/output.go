@@ -3,39 +3,101 @@ package collections
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
+	"github.com/deadloct/immutablex-go-lib/output"
 	"github.com/deadloct/immutablex-go-lib/utils"
 	"github.com/immutable/imx-core-sdk-golang/imx/api"
 	log "github.com/sirupsen/logrus"
 )
 
-func PrintCollectionJSON(collection *api.Collection) {
+// DefaultFields are the fields used by the tabular output formats when none
+// are selected.
+var DefaultFields = []string{"name", "address", "immutascan_url"}
+
+func WriteCollectionJSON(w io.Writer, collection *api.Collection) error {
 	data, err := json.MarshalIndent(collection, "", "  ")
 	if err != nil {
-		log.Debugf("could not convert asset to json: %v\nasset: %#v\n", err, collection)
-		return
+		return fmt.Errorf("could not convert collection to json: %w", err)
 	}
 
-	fmt.Println(string(data))
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func PrintCollectionJSON(collection *api.Collection) {
+	if err := WriteCollectionJSON(os.Stdout, collection); err != nil {
+		log.Errorf("could not print collection: %v", err)
+	}
+}
+
+func getCollectionURL(collection *api.Collection) string {
+	return strings.Join([]string{utils.ImmutascanURL, "address", collection.Address}, "/")
+}
+
+func WriteCollectionStandard(w io.Writer, collection *api.Collection) error {
+	_, err := fmt.Fprintf(w, "%s: %s\n", collection.Name, getCollectionURL(collection))
+	return err
 }
 
 func PrintCollectionStandard(collection *api.Collection) {
-	url := strings.Join([]string{utils.ImmutascanURL, "address", collection.Address}, "/")
-	fmt.Printf("%s: %s\n", collection.Name, url)
+	if err := WriteCollectionStandard(os.Stdout, collection); err != nil {
+		log.Errorf("could not print collection: %v", err)
+	}
+}
+
+func writeFormatted(w io.Writer, collections []api.Collection, format string, single bool) error {
+	items := make([]interface{}, len(collections))
+	for i := range collections {
+		items[i] = collections[i]
+	}
+
+	extra := func(i int) map[string]interface{} {
+		return map[string]interface{}{"immutascan_url": getCollectionURL(&collections[i])}
+	}
+
+	return output.Write(w, format, single, items, DefaultFields, extra)
+}
+
+// WriteCollection writes the collection to w in the given format: "standard"
+// (the default) or any format registered with the output package, optionally
+// followed by the fields to include, e.g. "table=name,address", or a template
+// such as "template-file=collection.tmpl".
+func WriteCollection(w io.Writer, collection *api.Collection, format string) error {
+	if output.IsStandard(format) {
+		return WriteCollectionStandard(w, collection)
+	}
+
+	return writeFormatted(w, []api.Collection{*collection}, format, true)
+}
+
+// WriteCollections writes the collections to w in the given format, see
+// WriteCollection.
+func WriteCollections(w io.Writer, collections []api.Collection, format string) error {
+	if output.IsStandard(format) {
+		for _, col := range collections {
+			if err := WriteCollectionStandard(w, &col); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return writeFormatted(w, collections, format, false)
 }
 
-func PrintCollection(collection *api.Collection, output string) {
-	switch strings.ToLower(output) {
-	case "json":
-		PrintCollectionJSON(collection)
-	default:
-		PrintCollectionStandard(collection)
+// PrintCollection prints the collection to stdout, see WriteCollection.
+func PrintCollection(collection *api.Collection, format string) {
+	if err := WriteCollection(os.Stdout, collection, format); err != nil {
+		log.Errorf("could not print collection: %v", err)
 	}
 }
 
-func PrintCollections(collections []api.Collection, output string) {
-	for _, col := range collections {
-		PrintCollection(&col, output)
+// PrintCollections prints the collections to stdout, see WriteCollection.
+func PrintCollections(collections []api.Collection, format string) {
+	if err := WriteCollections(os.Stdout, collections, format); err != nil {
+		log.Errorf("could not print collections: %v", err)
 	}
 }
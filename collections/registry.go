@@ -0,0 +1,355 @@
+package collections
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	ErrShortcutNotFound  = errors.New("shortcut not found")
+	ErrDuplicateShortcut = errors.New("shortcut already exists")
+	ErrDuplicateAddress  = errors.New("address already has a shortcut")
+	ErrInvalidAddress    = errors.New("invalid address")
+	ErrInvalidShortcut   = errors.New("invalid shortcut")
+	ErrNoShortcutsFile   = errors.New("no shortcuts file set")
+)
+
+// ShortcutRegistry is an editable set of shortcuts backed by a JSON file in the
+// same format as DefaultShortcutsContent. Unlike NewShortcuts, malformed files
+// and invalid entries are errors.
+type ShortcutRegistry struct {
+	path      string
+	shortcuts []Shortcut
+
+	sync.Mutex
+}
+
+// LoadShortcutRegistry loads the shortcuts file at path. A missing file starts
+// the registry with the default shortcuts, which are written on Save.
+func LoadShortcutRegistry(path string) (*ShortcutRegistry, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		content = DefaultShortcutsContent
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read shortcuts file %s: %w", path, err)
+	}
+
+	shortcuts, err := ParseShortcuts(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shortcuts file %s: %w", path, err)
+	}
+
+	return &ShortcutRegistry{path: path, shortcuts: shortcuts}, nil
+}
+
+// NewShortcutRegistry returns a registry of the given shortcuts, saved to path
+// unless it's empty.
+func NewShortcutRegistry(path string, shortcuts []Shortcut) (*ShortcutRegistry, error) {
+	if err := validateShortcuts(shortcuts); err != nil {
+		return nil, err
+	}
+
+	return &ShortcutRegistry{path: path, shortcuts: append([]Shortcut(nil), shortcuts...)}, nil
+}
+
+// ParseShortcuts parses and validates a shortcuts file.
+func ParseShortcuts(content []byte) ([]Shortcut, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.DisallowUnknownFields()
+
+	var shortcuts []Shortcut
+	if err := dec.Decode(&shortcuts); err != nil {
+		return nil, err
+	}
+
+	if err := validateShortcuts(shortcuts); err != nil {
+		return nil, err
+	}
+
+	return shortcuts, nil
+}
+
+func validateShortcuts(shortcuts []Shortcut) error {
+	for i, s := range shortcuts {
+		if err := ValidateShortcut(s); err != nil {
+			return fmt.Errorf("entry %d: %w", i+1, err)
+		}
+
+		for _, prev := range shortcuts[:i] {
+			if err := conflict(prev, s); err != nil {
+				return fmt.Errorf("entry %d: %w", i+1, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateShortcut checks a shortcut has a name, a key without whitespace and
+// a valid address.
+func ValidateShortcut(s Shortcut) error {
+	switch {
+	case s.Shortcut == "":
+		return fmt.Errorf("%w: shortcut is required", ErrInvalidShortcut)
+	case strings.IndexFunc(s.Shortcut, unicode.IsSpace) >= 0:
+		return fmt.Errorf("%w: %q contains whitespace", ErrInvalidShortcut, s.Shortcut)
+	case IsAddress(s.Shortcut):
+		return fmt.Errorf("%w: %q is an address", ErrInvalidShortcut, s.Shortcut)
+	case s.Name == "":
+		return fmt.Errorf("%w: %s has no name", ErrInvalidShortcut, s.Shortcut)
+	}
+
+	if err := ValidateAddress(s.Addr); err != nil {
+		return fmt.Errorf("%s: %w", s.Shortcut, err)
+	}
+
+	return nil
+}
+
+func conflict(a, b Shortcut) error {
+	if strings.EqualFold(a.Shortcut, b.Shortcut) {
+		return fmt.Errorf("%w: %s", ErrDuplicateShortcut, b.Shortcut)
+	}
+
+	if strings.EqualFold(a.Addr, b.Addr) {
+		return fmt.Errorf("%w: %s is %s", ErrDuplicateAddress, b.Addr, a.Shortcut)
+	}
+
+	return nil
+}
+
+// IsAddress reports whether s looks like a 0x prefixed 20 byte hex address.
+func IsAddress(s string) bool {
+	if len(s) != 42 || !strings.HasPrefix(s, "0x") {
+		return false
+	}
+
+	_, err := hex.DecodeString(s[2:])
+	return err == nil
+}
+
+// ValidateAddress checks addr is a hex address and, if it's mixed case, that
+// its EIP-55 checksum is correct. All lower or upper case addresses carry no
+// checksum.
+func ValidateAddress(addr string) error {
+	if !IsAddress(addr) {
+		return fmt.Errorf("%w: %q", ErrInvalidAddress, addr)
+	}
+
+	hexPart := addr[2:]
+	if hexPart == strings.ToLower(hexPart) || hexPart == strings.ToUpper(hexPart) {
+		return nil
+	}
+
+	if addr != ChecksumAddress(addr) {
+		return fmt.Errorf("%w: %s has a bad checksum, expected %s", ErrInvalidAddress, addr, ChecksumAddress(addr))
+	}
+
+	return nil
+}
+
+// ChecksumAddress returns the EIP-55 mixed case form of an address.
+func ChecksumAddress(addr string) string {
+	lower := strings.ToLower(strings.TrimPrefix(addr, "0x"))
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(lower))
+	hash := hex.EncodeToString(h.Sum(nil))
+
+	out := []byte(lower)
+	for i, c := range out {
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+
+	return "0x" + string(out)
+}
+
+func (r *ShortcutRegistry) Path() string {
+	return r.path
+}
+
+// List returns the shortcuts sorted by key.
+func (r *ShortcutRegistry) List() []Shortcut {
+	r.Lock()
+	defer r.Unlock()
+
+	list := append([]Shortcut(nil), r.shortcuts...)
+	sort.Slice(list, func(i, j int) bool { return list[i].Shortcut < list[j].Shortcut })
+	return list
+}
+
+// Shortcuts returns a snapshot of the registry as the map the clients use.
+func (r *ShortcutRegistry) Shortcuts() Shortcuts {
+	r.Lock()
+	defer r.Unlock()
+
+	s := make(Shortcuts, len(r.shortcuts))
+	for _, shortcut := range r.shortcuts {
+		s[shortcut.Shortcut] = shortcut
+	}
+
+	return s
+}
+
+// Get looks up a shortcut by its key, ignoring case like the duplicate checks.
+func (r *ShortcutRegistry) Get(shortcut string) (*Shortcut, bool) {
+	return r.find(func(s Shortcut) bool { return strings.EqualFold(s.Shortcut, shortcut) })
+}
+
+// GetByAddress looks up the shortcut of an address, ignoring case.
+func (r *ShortcutRegistry) GetByAddress(addr string) (*Shortcut, bool) {
+	return r.find(func(s Shortcut) bool { return strings.EqualFold(s.Addr, addr) })
+}
+
+// GetByName looks up a shortcut by its collection name, ignoring case.
+func (r *ShortcutRegistry) GetByName(name string) (*Shortcut, bool) {
+	return r.find(func(s Shortcut) bool { return strings.EqualFold(s.Name, name) })
+}
+
+func (r *ShortcutRegistry) find(match func(s Shortcut) bool) (*Shortcut, bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	for _, s := range r.shortcuts {
+		if match(s) {
+			return &s, true
+		}
+	}
+
+	return nil, false
+}
+
+// Add adds a shortcut, failing if its key or address is already registered.
+func (r *ShortcutRegistry) Add(s Shortcut) error {
+	if err := ValidateShortcut(s); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	for _, existing := range r.shortcuts {
+		if err := conflict(existing, s); err != nil {
+			return err
+		}
+	}
+
+	r.shortcuts = append(r.shortcuts, s)
+	return nil
+}
+
+func (r *ShortcutRegistry) Remove(shortcut string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	i := r.index(shortcut)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrShortcutNotFound, shortcut)
+	}
+
+	r.shortcuts = append(r.shortcuts[:i], r.shortcuts[i+1:]...)
+	return nil
+}
+
+// Rename changes a shortcut's key.
+func (r *ShortcutRegistry) Rename(oldShortcut, newShortcut string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	i := r.index(oldShortcut)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrShortcutNotFound, oldShortcut)
+	}
+
+	renamed := r.shortcuts[i]
+	renamed.Shortcut = newShortcut
+	if err := ValidateShortcut(renamed); err != nil {
+		return err
+	}
+
+	for j, existing := range r.shortcuts {
+		if j != i && strings.EqualFold(existing.Shortcut, newShortcut) {
+			return fmt.Errorf("%w: %s", ErrDuplicateShortcut, newShortcut)
+		}
+	}
+
+	r.shortcuts[i] = renamed
+	return nil
+}
+
+func (r *ShortcutRegistry) index(shortcut string) int {
+	for i, s := range r.shortcuts {
+		if strings.EqualFold(s.Shortcut, shortcut) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Save writes the registry to its file. The file is replaced atomically, so
+// readers never see a partial write, and keeps its permissions. New files are
+// created 0644.
+func (r *ShortcutRegistry) Save() error {
+	if r.path == "" {
+		return ErrNoShortcutsFile
+	}
+
+	content, err := json.MarshalIndent(r.List(), "", "    ")
+	if err != nil {
+		return err
+	}
+
+	mode := fs.FileMode(0o644)
+	if info, err := os.Stat(r.path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	dir := filepath.Dir(r.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(r.path)+".*")
+	if err != nil {
+		return err
+	}
+
+	// CreateTemp creates the file 0600, and the data must be on disk before
+	// the rename makes it visible.
+	err = tmp.Chmod(mode)
+	if err == nil {
+		_, err = tmp.Write(append(content, '\n'))
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("could not save shortcuts file %s: %w", r.path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("could not save shortcuts file %s: %w", r.path, err)
+	}
+
+	return nil
+}
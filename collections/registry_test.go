@@ -0,0 +1,92 @@
+package collections
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegistrySaveKeepsMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shortcuts.json")
+
+	r, err := LoadShortcutRegistry(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o644 {
+		t.Fatalf("new file has mode %v, %v, want 0644", info.Mode().Perm(), err)
+	}
+
+	if err := os.Chmod(path, 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	r.Add(Shortcut{Name: "Test", Addr: "0x1234567890123456789012345678901234567890", Shortcut: "test"})
+	if err := r.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o640 {
+		t.Errorf("saved file has mode %v, want 0640", info.Mode().Perm())
+	}
+
+	saved, err := LoadShortcutRegistry(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := saved.Get("test"); !ok {
+		t.Error("added shortcut wasn't saved")
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("got %d files, want the temporary file removed", len(entries))
+	}
+}
+
+func TestRegistryIgnoresCase(t *testing.T) {
+	r, err := NewShortcutRegistry("", []Shortcut{
+		{Name: "BitVerse Heroes", Addr: "0x6465ef3009f3c474774f4afb607a5d600ea71d95", Shortcut: "Hero"},
+		{Name: "BitVerse Portals", Addr: "0xe4ac52f4b4a721d1d0ad8c9c689df401c2db7291", Shortcut: "portal"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s, ok := r.Get("hero"); !ok || s.Shortcut != "Hero" {
+		t.Errorf("Get(hero) = %v, %t", s, ok)
+	}
+
+	err = r.Add(Shortcut{Name: "Other", Addr: "0x1234567890123456789012345678901234567890", Shortcut: "HERO"})
+	if !errors.Is(err, ErrDuplicateShortcut) {
+		t.Errorf("Add(HERO) = %v, want %v", err, ErrDuplicateShortcut)
+	}
+
+	if err := r.Rename("HERO", "heroes"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Rename("Heroes", "PORTAL"); !errors.Is(err, ErrDuplicateShortcut) {
+		t.Errorf("Rename to PORTAL = %v, want %v", err, ErrDuplicateShortcut)
+	}
+
+	// Changing only the case of a shortcut isn't a conflict with itself.
+	if err := r.Rename("heroes", "Heroes"); err != nil {
+		t.Error(err)
+	}
+
+	if err := r.Remove("PORTAL"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Remove("portal"); !errors.Is(err, ErrShortcutNotFound) {
+		t.Errorf("Remove of a removed shortcut = %v, want %v", err, ErrShortcutNotFound)
+	}
+}
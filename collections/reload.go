@@ -0,0 +1,235 @@
+package collections
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+)
+
+// reloadDelay collects the burst of events editors and atomic saves produce
+// into one reload.
+const reloadDelay = 100 * time.Millisecond
+
+var (
+	current     atomic.Value
+	currentOnce sync.Once
+
+	subscribers      = map[int]func(Shortcuts){}
+	nextSubscriberID int
+	subscribersMu    sync.Mutex
+)
+
+// LoadShortcuts loads every shortcut source with ctx, fetching ShortcutURL,
+// and makes them the current shortcuts. Programs call it once at startup so
+// lookups never wait on the network.
+func LoadShortcuts(ctx context.Context) Shortcuts {
+	s := shortcutsOf(LoadLayeredShortcuts(ctx))
+	SetShortcuts(s)
+	return s
+}
+
+// CurrentShortcuts returns the shortcuts the clients resolve with. Unless
+// LoadShortcuts was called they're loaded with NewShortcuts on first use. They
+// are replaced by SetShortcuts or a ShortcutsWatcher. The returned map must
+// not be modified.
+func CurrentShortcuts() Shortcuts {
+	currentOnce.Do(func() {
+		if current.Load() == nil {
+			current.Store(NewShortcuts())
+		}
+	})
+
+	return current.Load().(Shortcuts)
+}
+
+// SetShortcuts atomically replaces the shortcuts used by every client and
+// notifies subscribers.
+func SetShortcuts(s Shortcuts) {
+	currentOnce.Do(func() {})
+	current.Store(s)
+
+	subscribersMu.Lock()
+	fns := make([]func(Shortcuts), 0, len(subscribers))
+	for _, fn := range subscribers {
+		fns = append(fns, fn)
+	}
+	subscribersMu.Unlock()
+
+	for _, fn := range fns {
+		fn(s)
+	}
+}
+
+// SubscribeShortcuts calls fn with the new shortcuts whenever they're replaced.
+// The returned function unsubscribes.
+func SubscribeShortcuts(fn func(Shortcuts)) func() {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+
+	id := nextSubscriberID
+	nextSubscriberID++
+	subscribers[id] = fn
+
+	return func() {
+		subscribersMu.Lock()
+		defer subscribersMu.Unlock()
+		delete(subscribers, id)
+	}
+}
+
+// ShortcutsWatcher reloads the shortcuts when a shortcut file changes. Files
+// that fail to parse or validate are logged and ignored, keeping the previous
+// shortcuts.
+type ShortcutsWatcher struct {
+	paths   map[string]bool
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// WatchShortcuts loads the shortcuts, makes them current and reloads them
+// whenever the user file, the project file or ShortcutLocation changes, until
+// Close. Reloads don't fetch ShortcutURL; its cached copy is used.
+func WatchShortcuts() (*ShortcutsWatcher, error) {
+	paths := shortcutFilePaths()
+	if err := reloadShortcuts(paths); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &ShortcutsWatcher{paths: make(map[string]bool), watcher: watcher, done: make(chan struct{})}
+	for _, path := range paths {
+		w.paths[path] = true
+
+		// Watch the directory rather than the file, as atomic saves replace
+		// the file and would end a watch on it.
+		dir := filepath.Dir(path)
+		if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+			log.Debugf("not watching shortcuts file %s, %s doesn't exist", path, dir)
+			continue
+		}
+
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return nil, fmt.Errorf("could not watch shortcuts file %s: %w", path, err)
+		}
+	}
+
+	w.wg.Add(1)
+	go w.run()
+
+	return w, nil
+}
+
+func (w *ShortcutsWatcher) run() {
+	defer w.wg.Done()
+
+	var (
+		timer  *time.Timer
+		reload <-chan time.Time
+	)
+
+	for {
+		select {
+		case <-w.done:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+
+			if !w.paths[filepath.Clean(event.Name)] || !event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) {
+				continue
+			}
+
+			if timer == nil {
+				timer = time.NewTimer(reloadDelay)
+			} else {
+				timer.Reset(reloadDelay)
+			}
+			reload = timer.C
+		case <-reload:
+			reload = nil
+			if err := reloadShortcuts(w.sortedPaths()); err != nil {
+				log.Errorf("keeping previous shortcuts: %v", err)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Errorf("shortcuts watcher error: %v", err)
+		}
+	}
+}
+
+func (w *ShortcutsWatcher) Close() error {
+	close(w.done)
+	err := w.watcher.Close()
+	w.wg.Wait()
+	return err
+}
+
+func (w *ShortcutsWatcher) sortedPaths() []string {
+	paths := make([]string, 0, len(w.paths))
+	for path := range w.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// shortcutFilePaths returns the file sources, whether or not they exist.
+func shortcutFilePaths() []string {
+	var paths []string
+	for _, path := range []string{userShortcutsPath(), findProjectShortcuts(), ShortcutLocation} {
+		if path != "" {
+			paths = append(paths, filepath.Clean(path))
+		}
+	}
+
+	return paths
+}
+
+// reloadShortcuts validates the shortcut files, then reloads every source so
+// the files keep their place among them. A missing ShortcutLocation is an
+// error, as atomic saves briefly remove the file and the create event follows.
+func reloadShortcuts(paths []string) error {
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist) && path == filepath.Clean(ShortcutLocation):
+			return fmt.Errorf("shortcuts file %s is missing", path)
+		case errors.Is(err, os.ErrNotExist):
+			continue
+		case err != nil:
+			return fmt.Errorf("could not read shortcuts file %s: %w", path, err)
+		}
+
+		if _, err := ParseShortcuts(content); err != nil {
+			return fmt.Errorf("invalid shortcuts file %s: %w", path, err)
+		}
+	}
+
+	s := NewShortcuts()
+	log.Debugf("reloaded %d shortcuts from %s", len(s), strings.Join(paths, ", "))
+	SetShortcuts(s)
+	return nil
+}
@@ -0,0 +1,143 @@
+package collections
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func shortcutsJSON(shortcut string) []byte {
+	return []byte(fmt.Sprintf(`[{"name": "Test", "addr": "0x1234567890123456789012345678901234567890", "shortcut": %q}]`, shortcut))
+}
+
+// waitForShortcut waits for a reload to add shortcut.
+func waitForShortcut(t *testing.T, shortcut string) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for CurrentShortcuts().GetShortcutByName(shortcut) == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("shortcuts weren't reloaded with %s", shortcut)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func watchShortcuts(t *testing.T) *ShortcutsWatcher {
+	t.Helper()
+
+	w, err := WatchShortcuts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { w.Close() })
+
+	return w
+}
+
+func TestWatchShortcutsReloadsEveryFile(t *testing.T) {
+	isolateSources(t)
+
+	userPath := userShortcutsPath()
+	if err := os.MkdirAll(filepath.Dir(userPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	ShortcutLocation = filepath.Join(t.TempDir(), "shortcuts.json")
+	if err := os.WriteFile(ShortcutLocation, shortcutsJSON("first"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	watchShortcuts(t)
+	if CurrentShortcuts().GetShortcutByName("first") == nil {
+		t.Fatal("shortcuts weren't loaded")
+	}
+
+	os.WriteFile(ShortcutLocation, shortcutsJSON("second"), 0o644)
+	waitForShortcut(t, "second")
+
+	// The user file doesn't exist yet, but its directory is watched.
+	os.WriteFile(userPath, shortcutsJSON("user"), 0o644)
+	waitForShortcut(t, "user")
+
+	// Atomic saves replace the file.
+	tmp := ShortcutLocation + ".tmp"
+	os.WriteFile(tmp, shortcutsJSON("third"), 0o644)
+	if err := os.Rename(tmp, ShortcutLocation); err != nil {
+		t.Fatal(err)
+	}
+	waitForShortcut(t, "third")
+}
+
+func TestWatchShortcutsDebounces(t *testing.T) {
+	isolateSources(t)
+
+	ShortcutLocation = filepath.Join(t.TempDir(), "shortcuts.json")
+	os.WriteFile(ShortcutLocation, shortcutsJSON("first"), 0o644)
+	watchShortcuts(t)
+
+	var reloads int32
+	unsubscribe := SubscribeShortcuts(func(Shortcuts) { atomic.AddInt32(&reloads, 1) })
+	defer unsubscribe()
+
+	for i := 0; i < 5; i++ {
+		os.WriteFile(ShortcutLocation, shortcutsJSON(fmt.Sprintf("burst%d", i)), 0o644)
+	}
+
+	waitForShortcut(t, "burst4")
+	time.Sleep(3 * reloadDelay)
+
+	if n := atomic.LoadInt32(&reloads); n != 1 {
+		t.Errorf("got %d reloads for a burst of writes, want 1", n)
+	}
+}
+
+func TestWatchShortcutsKeepsPreviousOnInvalidFile(t *testing.T) {
+	isolateSources(t)
+
+	ShortcutLocation = filepath.Join(t.TempDir(), "shortcuts.json")
+	os.WriteFile(ShortcutLocation, shortcutsJSON("first"), 0o644)
+	watchShortcuts(t)
+
+	var reloads int32
+	unsubscribe := SubscribeShortcuts(func(Shortcuts) { atomic.AddInt32(&reloads, 1) })
+	defer unsubscribe()
+
+	for _, content := range []string{
+		`not json`,
+		`[{"name": "Bad", "addr": "0x123", "shortcut": "bad"}]`,
+		`[{"name": "Unknown", "addr": "0x1234567890123456789012345678901234567890", "shortcut": "x", "extra": 1}]`,
+	} {
+		os.WriteFile(ShortcutLocation, []byte(content), 0o644)
+		time.Sleep(3 * reloadDelay)
+	}
+
+	if n := atomic.LoadInt32(&reloads); n != 0 {
+		t.Errorf("invalid files replaced the shortcuts %d times", n)
+	}
+
+	if CurrentShortcuts().GetShortcutByName("first") == nil {
+		t.Error("previous shortcuts weren't kept")
+	}
+
+	// A fixed file is picked up again.
+	os.WriteFile(ShortcutLocation, shortcutsJSON("fixed"), 0o644)
+	waitForShortcut(t, "fixed")
+}
+
+func TestWatchShortcutsRejectsInvalidFiles(t *testing.T) {
+	isolateSources(t)
+
+	ShortcutLocation = filepath.Join(t.TempDir(), "shortcuts.json")
+	if _, err := WatchShortcuts(); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+
+	os.WriteFile(ShortcutLocation, []byte(`[{"shortcut": "x"}]`), 0o644)
+	if _, err := WatchShortcuts(); err == nil {
+		t.Error("expected an error for an invalid file")
+	}
+}
@@ -0,0 +1,175 @@
+package collections
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// WalletAliasLocation is a JSON file of wallet aliases, e.g.
+// {"treasury": "0x..."}, used wherever a user address is expected.
+var WalletAliasLocation string
+
+func init() {
+	WalletAliasLocation = os.Getenv("IMX_WALLET_ALIAS_LOCATION")
+}
+
+// WalletAliases maps aliases to wallet addresses.
+type WalletAliases map[string]string
+
+var (
+	currentWallets     atomic.Value
+	currentWalletsOnce sync.Once
+)
+
+// LoadWalletAliases reads and validates a wallet alias file.
+func LoadWalletAliases(path string) (WalletAliases, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read wallet aliases %s: %w", path, err)
+	}
+
+	var aliases WalletAliases
+	if err := json.Unmarshal(content, &aliases); err != nil {
+		return nil, fmt.Errorf("invalid wallet aliases %s: %w", path, err)
+	}
+
+	for alias, addr := range aliases {
+		if err := ValidateAddress(addr); err != nil {
+			return nil, fmt.Errorf("invalid wallet alias %s in %s: %w", alias, path, err)
+		}
+	}
+
+	return aliases, nil
+}
+
+// CurrentWalletAliases returns the aliases the clients resolve with, loaded
+// from WalletAliasLocation on first use.
+func CurrentWalletAliases() WalletAliases {
+	currentWalletsOnce.Do(func() {
+		if currentWallets.Load() != nil {
+			return
+		}
+
+		aliases := WalletAliases{}
+		if WalletAliasLocation != "" {
+			loaded, err := LoadWalletAliases(WalletAliasLocation)
+			if err != nil {
+				log.Errorf("not using wallet aliases: %v", err)
+			} else {
+				aliases = loaded
+			}
+		}
+
+		currentWallets.Store(aliases)
+	})
+
+	return currentWallets.Load().(WalletAliases)
+}
+
+// SetWalletAliases atomically replaces the aliases used by every client.
+func SetWalletAliases(aliases WalletAliases) {
+	currentWalletsOnce.Do(func() {})
+	currentWallets.Store(aliases)
+}
+
+// Resolver replaces collection shortcuts and wallet aliases in address fields
+// with addresses. Values that aren't known are returned unchanged, so
+// addresses pass straight through.
+type Resolver struct {
+	shortcuts func() Shortcuts
+	wallets   func() WalletAliases
+}
+
+// DefaultResolver resolves with the current shortcuts and wallet aliases. All
+// backends use it.
+var DefaultResolver = NewResolver(CurrentShortcuts, CurrentWalletAliases)
+
+func NewResolver(shortcuts func() Shortcuts, wallets func() WalletAliases) *Resolver {
+	return &Resolver{shortcuts: shortcuts, wallets: wallets}
+}
+
+// Collection resolves a token or collection address field. field names the
+// field in debug logs.
+func (r *Resolver) Collection(field, value string) string {
+	if value == "" {
+		return value
+	}
+
+	if s := r.shortcuts().GetShortcutByName(value); s != nil {
+		log.Debugf("resolved %s %q to %s (shortcut for %s)", field, value, s.Addr, s.Name)
+		return s.Addr
+	}
+
+	return value
+}
+
+// Collections resolves a comma separated list of collection addresses, e.g.
+// a whitelist.
+func (r *Resolver) Collections(field, value string) string {
+	if value == "" {
+		return value
+	}
+
+	parts := strings.Split(value, ",")
+	for i, p := range parts {
+		parts[i] = r.Collection(field, strings.TrimSpace(p))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// Wallet resolves a user address field.
+func (r *Resolver) Wallet(field, value string) string {
+	if value == "" {
+		return value
+	}
+
+	if addr, ok := r.wallets()[value]; ok {
+		log.Debugf("resolved %s %q to %s (wallet alias)", field, value, addr)
+		return addr
+	}
+
+	return value
+}
+
+// Wallets resolves a comma separated list of user addresses.
+func (r *Resolver) Wallets(field, value string) string {
+	if value == "" {
+		return value
+	}
+
+	parts := strings.Split(value, ",")
+	for i, p := range parts {
+		parts[i] = r.Wallet(field, strings.TrimSpace(p))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// ResolveCollection resolves a collection address field with DefaultResolver.
+func ResolveCollection(field, value string) string {
+	return DefaultResolver.Collection(field, value)
+}
+
+// ResolveCollections resolves a comma separated list of collection addresses
+// with DefaultResolver.
+func ResolveCollections(field, value string) string {
+	return DefaultResolver.Collections(field, value)
+}
+
+// ResolveWallet resolves a user address field with DefaultResolver.
+func ResolveWallet(field, value string) string {
+	return DefaultResolver.Wallet(field, value)
+}
+
+// ResolveWallets resolves a comma separated list of user addresses with
+// DefaultResolver.
+func ResolveWallets(field, value string) string {
+	return DefaultResolver.Wallets(field, value)
+}
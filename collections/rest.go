@@ -2,10 +2,12 @@ package collections
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 
+	"github.com/deadloct/immutablex-go-lib/metrics"
+	"github.com/deadloct/immutablex-go-lib/utils"
 	"github.com/immutable/imx-core-sdk-golang/imx/api"
 	log "github.com/sirupsen/logrus"
 )
@@ -16,20 +18,26 @@ const (
 )
 
 type RESTClientConfig struct {
-	url string
+	URL string
+
+	// HTTPClient sends every request, a new http.Client is used when nil.
+	HTTPClient *http.Client
 }
 
 type RESTClient struct {
-	client    *http.Client
-	url       string
-	shortcuts Shortcuts
+	client *http.Client
+	url    string
 }
 
 func NewRESTClient(cfg RESTClientConfig) *RESTClient {
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+
 	return &RESTClient{
-		url:       cfg.url,
-		client:    &http.Client{},
-		shortcuts: NewShortcuts(),
+		url:    cfg.URL,
+		client: client,
 	}
 }
 
@@ -38,21 +46,12 @@ func (c *RESTClient) Start() error { return nil }
 func (c *RESTClient) Stop() {}
 
 func (c *RESTClient) GetCollection(ctx context.Context, collection string) (*api.Collection, error) {
-	if v := c.shortcuts.GetShortcutByName(collection); v != nil {
-		collection = v.Addr
-	}
+	collection = ResolveCollection("collection", collection)
 
 	log.Debugf("fetching collection %s", collection)
 	url := c.url + GetCollectionEndpoint + "/" + collection
-	resp, err := c.client.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var result api.Collection
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		log.Errorf("could not parse response from server: %#v", err)
+	if err := utils.GetJSON(ctx, c.client, metrics.EndpointGetCollection, url, &result); err != nil {
 		return nil, err
 	}
 
@@ -61,19 +60,13 @@ func (c *RESTClient) GetCollection(ctx context.Context, collection string) (*api
 
 func (c *RESTClient) ListCollections(ctx context.Context, cfg *ListCollectionsConfig) ([]api.Collection, error) {
 	url := c.getListCollectionsURL(cfg)
-	resp, err := c.client.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var parsed api.ListCollectionsResponse
-
-	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
-		log.Errorf("could not parse response from server: %#v", err)
+	if err := utils.GetJSON(ctx, c.client, metrics.EndpointListCollections, url, &parsed); err != nil {
 		return nil, err
 	}
 
+	metrics.GetRecorder().Page(metrics.EndpointListCollections, len(parsed.Result))
+
 	if len(parsed.Result) == 0 {
 		return cfg.Collections, nil
 	}
@@ -81,11 +74,12 @@ func (c *RESTClient) ListCollections(ctx context.Context, cfg *ListCollectionsCo
 	cfg.Collections = append(cfg.Collections, parsed.Result...)
 	cfg.Cursor = parsed.Cursor
 
-	first := *parsed.Result[0].UpdatedAt.Get()
-	last := *parsed.Result[len(parsed.Result)-1].UpdatedAt.Get()
+	first := parsed.Result[0].GetUpdatedAt()
+	last := parsed.Result[len(parsed.Result)-1].GetUpdatedAt()
 	log.Debugf("fetched %v collections from %v to %v", len(parsed.Result), first, last)
 
-	if parsed.Remaining > 0 {
+	getMore := len(cfg.Collections) < cfg.PageSize || cfg.PageSize == 0
+	if parsed.Remaining > 0 && getMore {
 		return c.ListCollections(ctx, cfg)
 	}
 
@@ -96,7 +90,7 @@ func (c *RESTClient) getListCollectionsURL(cfg *ListCollectionsConfig) string {
 	v := url.Values{}
 
 	if cfg.Blacklist != "" {
-		v.Set("blacklist", cfg.Blacklist)
+		v.Set("blacklist", ResolveCollections("blacklist", cfg.Blacklist))
 	}
 
 	if cfg.Cursor != "" {
@@ -115,8 +109,12 @@ func (c *RESTClient) getListCollectionsURL(cfg *ListCollectionsConfig) string {
 		v.Set("order_by", cfg.OrderBy)
 	}
 
+	if cfg.PageSize > 0 {
+		v.Set("page_size", fmt.Sprint(cfg.PageSize-len(cfg.Collections)))
+	}
+
 	if cfg.Whitelist != "" {
-		v.Set("whitelist", cfg.Whitelist)
+		v.Set("whitelist", ResolveCollections("whitelist", cfg.Whitelist))
 	}
 
 	return c.url + ListCollectionsEndpoint + "?" + v.Encode()
@@ -1,8 +1,7 @@
 package collections
 
 import (
-	"encoding/json"
-	"io/ioutil"
+	"context"
 	"os"
 
 	log "github.com/sirupsen/logrus"
@@ -37,31 +36,22 @@ type Shortcut struct {
 
 type Shortcuts map[string]Shortcut
 
+// NewShortcuts loads and merges every shortcut source, see
+// LoadLayeredShortcuts, without fetching ShortcutURL: its cached copy from the
+// last LoadShortcuts is used instead. Sources that fail to load are logged and
+// skipped.
 func NewShortcuts() Shortcuts {
-	content := DefaultShortcutsContent
-	if ShortcutLocation != "" {
-		if _, err := os.Stat(ShortcutLocation); err == nil {
-			content, err = ioutil.ReadFile(ShortcutLocation)
-			if err != nil {
-				log.Debugf("could not load shortcuts file %s: %v", ShortcutLocation, err)
-				content = DefaultShortcutsContent
-			}
-		} else {
-			log.Debugf("could not stat shortcuts file %s: %v", ShortcutLocation, err)
-		}
-	}
-
-	var data []Shortcut
-	if err := json.Unmarshal(content, &data); err != nil {
-		log.Debugf("could not parse shortcuts file %s: %v", ShortcutLocation, err)
-	}
+	return shortcutsOf(loadLayers(context.Background(), false))
+}
 
-	s := make(map[string]Shortcut, len(data))
-	for _, shortcut := range data {
-		s[shortcut.Shortcut] = shortcut
+func shortcutsOf(layered *LayeredShortcuts) Shortcuts {
+	for _, layer := range layered.Layers {
+		if layer.Err != nil {
+			log.Errorf("could not load %s shortcuts %s: %v", layer.Source, layer.Location, layer.Err)
+		}
 	}
 
-	return s
+	return layered.Shortcuts()
 }
 
 func (s Shortcuts) GetShortcutByName(name string) *Shortcut {
@@ -0,0 +1,298 @@
+package collections
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Shortcut sources, lowest priority first. Later sources override earlier
+// ones with the same shortcut.
+const (
+	SourceBuiltin = "builtin"
+	SourceUser    = "user"
+	SourceProject = "project"
+	SourceRemote  = "remote"
+	SourceEnv     = "env"
+)
+
+// ProjectShortcutsFile is looked for in the working directory and its parents.
+const ProjectShortcutsFile = ".imx/shortcuts.json"
+
+const remoteTimeout = 5 * time.Second
+
+// ShortcutURL is an optional URL serving a shortcuts file. Responses are cached
+// with their ETag and the cached copy is used when the server is unreachable.
+var ShortcutURL string
+
+func init() {
+	ShortcutURL = os.Getenv("IMX_SHORTCUT_URL")
+}
+
+// ShortcutLayer is one loaded source.
+type ShortcutLayer struct {
+	Source    string
+	Location  string
+	Shortcuts []Shortcut
+	// Err is set when the source exists but couldn't be loaded. Its shortcuts
+	// are then left out.
+	Err error
+}
+
+// SourcedShortcut is a merged shortcut and where it came from.
+type SourcedShortcut struct {
+	Shortcut
+	Source   string `json:"source"`
+	Location string `json:"location"`
+	// Overrides lists the lower priority sources that defined the shortcut.
+	Overrides []string `json:"overrides,omitempty"`
+}
+
+// LayeredShortcuts is the merge of every shortcut source.
+type LayeredShortcuts struct {
+	Layers  []ShortcutLayer
+	Entries map[string]SourcedShortcut
+}
+
+// LoadLayeredShortcuts loads the built-in defaults, the user file in
+// $XDG_CONFIG_HOME/imx/shortcuts.json, the nearest project file, ShortcutURL
+// and finally ShortcutLocation, each overriding the ones before. Missing
+// sources are skipped.
+func LoadLayeredShortcuts(ctx context.Context) *LayeredShortcuts {
+	return loadLayers(ctx, true)
+}
+
+// loadLayers loads every source. Without fetch, ShortcutURL is read from its
+// cached copy and skipped when there's none.
+func loadLayers(ctx context.Context, fetch bool) *LayeredShortcuts {
+	layers := []ShortcutLayer{parseLayer(SourceBuiltin, "", DefaultShortcutsContent)}
+
+	if path := userShortcutsPath(); path != "" {
+		layers = appendFileLayer(layers, SourceUser, path)
+	}
+
+	if path := findProjectShortcuts(); path != "" {
+		layers = appendFileLayer(layers, SourceProject, path)
+	}
+
+	switch {
+	case ShortcutURL == "":
+	case fetch:
+		content, err := fetchRemoteShortcuts(ctx, ShortcutURL)
+		if err != nil {
+			layers = append(layers, ShortcutLayer{Source: SourceRemote, Location: ShortcutURL, Err: err})
+		} else {
+			layers = append(layers, parseLayer(SourceRemote, ShortcutURL, content))
+		}
+	default:
+		contentPath, _ := remoteCachePaths(ShortcutURL)
+		if content, err := os.ReadFile(contentPath); err == nil {
+			layers = append(layers, parseLayer(SourceRemote, ShortcutURL, content))
+		} else {
+			log.Debugf("no cached shortcuts for %s: %v", ShortcutURL, err)
+		}
+	}
+
+	if ShortcutLocation != "" {
+		layers = appendFileLayer(layers, SourceEnv, ShortcutLocation)
+	}
+
+	return mergeLayers(layers)
+}
+
+// Shortcuts returns the merged shortcuts as the map the clients use.
+func (l *LayeredShortcuts) Shortcuts() Shortcuts {
+	s := make(Shortcuts, len(l.Entries))
+	for key, e := range l.Entries {
+		s[key] = e.Shortcut
+	}
+
+	return s
+}
+
+// List returns the merged shortcuts sorted by key.
+func (l *LayeredShortcuts) List() []SourcedShortcut {
+	list := make([]SourcedShortcut, 0, len(l.Entries))
+	for _, e := range l.Entries {
+		list = append(list, e)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Shortcut.Shortcut < list[j].Shortcut.Shortcut })
+	return list
+}
+
+func mergeLayers(layers []ShortcutLayer) *LayeredShortcuts {
+	merged := &LayeredShortcuts{Layers: layers, Entries: make(map[string]SourcedShortcut)}
+	for _, layer := range layers {
+		if layer.Err != nil {
+			log.Debugf("skipping %s shortcuts: %v", layer.Source, layer.Err)
+			continue
+		}
+
+		for _, s := range layer.Shortcuts {
+			entry := SourcedShortcut{Shortcut: s, Source: layer.Source, Location: layer.Location}
+			if prev, ok := merged.Entries[s.Shortcut]; ok {
+				entry.Overrides = append(prev.Overrides, prev.Source)
+			}
+			merged.Entries[s.Shortcut] = entry
+		}
+	}
+
+	return merged
+}
+
+func parseLayer(source, location string, content []byte) ShortcutLayer {
+	shortcuts, err := ParseShortcuts(content)
+	return ShortcutLayer{Source: source, Location: location, Shortcuts: shortcuts, Err: err}
+}
+
+func appendFileLayer(layers []ShortcutLayer, source, path string) []ShortcutLayer {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return layers
+	} else if err != nil {
+		return append(layers, ShortcutLayer{Source: source, Location: path, Err: err})
+	}
+
+	return append(layers, parseLayer(source, path, content))
+}
+
+func userShortcutsPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		var err error
+		if dir, err = os.UserConfigDir(); err != nil {
+			return ""
+		}
+	}
+
+	return filepath.Join(dir, "imx", "shortcuts.json")
+}
+
+func findProjectShortcuts() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectShortcutsFile)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func remoteCachePaths(url string) (string, string) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+
+	// Hashed so distinct URLs never share a cache file.
+	sum := sha256.Sum256([]byte(url))
+	base := filepath.Join(dir, "imx", "shortcuts-"+hex.EncodeToString(sum[:]))
+	return base + ".json", base + ".etag"
+}
+
+// fetchRemoteShortcuts downloads a shortcuts file, revalidating the cached copy
+// with its ETag. The cached copy is used when the server can't be reached or
+// serves an invalid file.
+func fetchRemoteShortcuts(ctx context.Context, url string) ([]byte, error) {
+	contentPath, etagPath := remoteCachePaths(url)
+	cached, cacheErr := os.ReadFile(contentPath)
+	haveCache := cacheErr == nil
+
+	etag := ""
+	if haveCache {
+		if data, err := os.ReadFile(etagPath); err == nil {
+			etag = string(data)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, remoteTimeout)
+	defer cancel()
+
+	content, newETag, err := getRemote(ctx, url, etag)
+	if err == nil && content == nil {
+		log.Debugf("shortcuts from %s not modified", url)
+		return cached, nil
+	}
+
+	if err == nil {
+		if _, err = ParseShortcuts(content); err != nil {
+			err = fmt.Errorf("invalid shortcuts from %s: %w", url, err)
+		}
+	}
+
+	if err != nil {
+		if haveCache {
+			log.Errorf("using cached shortcuts for %s: %v", url, err)
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(contentPath), 0o755); err != nil {
+		log.Debugf("could not cache shortcuts from %s: %v", url, err)
+	} else if err := os.WriteFile(contentPath, content, 0o644); err != nil {
+		log.Debugf("could not cache shortcuts from %s: %v", url, err)
+	} else if err := os.WriteFile(etagPath, []byte(newETag), 0o644); err != nil {
+		log.Debugf("could not cache shortcuts etag from %s: %v", url, err)
+	}
+
+	return content, nil
+}
+
+// getRemote returns nil content when the server answers 304 Not Modified.
+func getRemote(ctx context.Context, url, etag string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not fetch shortcuts from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && etag != "":
+		return nil, etag, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", fmt.Errorf("could not fetch shortcuts from %s: status %d", url, resp.StatusCode)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if content == nil {
+		content = []byte{}
+	}
+
+	return content, resp.Header.Get("ETag"), nil
+}
@@ -0,0 +1,65 @@
+package collections
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const remoteContent = `[{"name": "Remote", "addr": "0x1234567890123456789012345678901234567890", "shortcut": "remote"}]`
+
+// isolateSources points every shortcut source at empty temporary locations
+// and restores the current shortcuts afterwards.
+func isolateSources(t *testing.T) {
+	t.Helper()
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	prevURL, prevLocation, prevCurrent := ShortcutURL, ShortcutLocation, CurrentShortcuts()
+	ShortcutURL, ShortcutLocation = "", ""
+	t.Cleanup(func() {
+		ShortcutURL, ShortcutLocation = prevURL, prevLocation
+		SetShortcuts(prevCurrent)
+	})
+}
+
+func TestRemoteCachePathsDontCollide(t *testing.T) {
+	a, _ := remoteCachePaths("https://example.com/a-b.json")
+	b, _ := remoteCachePaths("https://example.com/a_b.json")
+	if a == b {
+		t.Errorf("distinct URLs share the cache file %s", a)
+	}
+}
+
+func TestOnlyLoadShortcutsFetchesRemote(t *testing.T) {
+	isolateSources(t)
+
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Write([]byte(remoteContent))
+	}))
+	defer srv.Close()
+	ShortcutURL = srv.URL
+
+	if s := NewShortcuts(); s.GetShortcutByName("remote") != nil || atomic.LoadInt32(&hits) != 0 {
+		t.Fatalf("NewShortcuts fetched %s", srv.URL)
+	}
+
+	s := LoadShortcuts(context.Background())
+	if s.GetShortcutByName("remote") == nil || atomic.LoadInt32(&hits) != 1 {
+		t.Fatalf("LoadShortcuts didn't fetch the remote shortcuts, %d requests", hits)
+	}
+
+	if CurrentShortcuts().GetShortcutByName("remote") == nil {
+		t.Error("LoadShortcuts didn't replace the current shortcuts")
+	}
+
+	// Later loads without fetching use the cached copy.
+	if s := NewShortcuts(); s.GetShortcutByName("remote") == nil || atomic.LoadInt32(&hits) != 1 {
+		t.Errorf("NewShortcuts didn't use the cached copy, %d requests", hits)
+	}
+}
@@ -0,0 +1,86 @@
+package graphql
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+type Config struct {
+	Assets      assets.Client
+	Collections collections.Client
+	Orders      orders.Client
+
+	// Prices defaults to coinbase.GetPriceClient().
+	Prices coinbase.PriceClient
+}
+
+type loadersKey struct{}
+
+// loaders are created per request so that results are only shared within it.
+type loaders struct {
+	collections *loader[string, *api.Collection]
+	prices      *loader[coinbase.SpotPair, float64]
+	sellOrders  *loader[sellOrdersKey, *orderConnectionResolver]
+}
+
+func newLoaders(cfg Config) *loaders {
+	return &loaders{
+		collections: newLoader(eachConcurrently(func(ctx context.Context, addr string) (*api.Collection, error) {
+			return cfg.Collections.GetCollection(ctx, addr)
+		})),
+		prices: newLoader(func(ctx context.Context, pairs []coinbase.SpotPair) map[coinbase.SpotPair]loaderResult[float64] {
+			prices := cfg.Prices.RetrieveSpotPrices(ctx, pairs)
+			results := make(map[coinbase.SpotPair]loaderResult[float64], len(pairs))
+			for _, pair := range pairs {
+				results[pair] = loaderResult[float64]{value: prices[pair]}
+			}
+			return results
+		}),
+		sellOrders: newLoader(batchSellOrders(cfg)),
+	}
+}
+
+// getLoaders returns the request's loaders, or new ones if the schema is
+// executed outside of Handler.
+func getLoaders(ctx context.Context, cfg Config) *loaders {
+	if l, ok := ctx.Value(loadersKey{}).(*loaders); ok {
+		return l
+	}
+
+	return newLoaders(cfg)
+}
+
+// NewSchema parses Schema with resolvers backed by the configured clients.
+func NewSchema(cfg Config) (*gql.Schema, error) {
+	if cfg.Prices == nil {
+		cfg.Prices = coinbase.GetPriceClient()
+	}
+
+	return gql.ParseSchema(Schema, &queryResolver{cfg: cfg})
+}
+
+// NewHandler returns an HTTP handler serving GraphQL queries over the clients.
+func NewHandler(cfg Config) (http.Handler, error) {
+	if cfg.Prices == nil {
+		cfg.Prices = coinbase.GetPriceClient()
+	}
+
+	schema, err := NewSchema(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &relay.Handler{Schema: schema}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), loadersKey{}, newLoaders(cfg))
+		h.ServeHTTP(w, r.WithContext(ctx))
+	}), nil
+}
@@ -0,0 +1,106 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// batchWait is how long a loader collects keys before fetching them together.
+const batchWait = 2 * time.Millisecond
+
+type loaderResult[V any] struct {
+	value V
+	err   error
+}
+
+type batchFunc[K comparable, V any] func(ctx context.Context, keys []K) map[K]loaderResult[V]
+
+// loader batches and caches lookups for the duration of one GraphQL request,
+// in the style of dataloader: keys requested by concurrently resolved fields
+// are collected for a short window and fetched with a single batch call.
+type loader[K comparable, V any] struct {
+	batch batchFunc[K, V]
+
+	cache   map[K]*loaderCall[V]
+	pending []K
+
+	sync.Mutex
+}
+
+type loaderCall[V any] struct {
+	done   chan struct{}
+	result loaderResult[V]
+}
+
+func newLoader[K comparable, V any](batch batchFunc[K, V]) *loader[K, V] {
+	return &loader[K, V]{batch: batch, cache: make(map[K]*loaderCall[V])}
+}
+
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.Lock()
+	call, ok := l.cache[key]
+	if !ok {
+		call = &loaderCall[V]{done: make(chan struct{})}
+		l.cache[key] = call
+		l.pending = append(l.pending, key)
+		if len(l.pending) == 1 {
+			time.AfterFunc(batchWait, func() { l.dispatch(ctx) })
+		}
+	}
+	l.Unlock()
+
+	select {
+	case <-call.done:
+		return call.result.value, call.result.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+func (l *loader[K, V]) dispatch(ctx context.Context) {
+	l.Lock()
+	keys := l.pending
+	l.pending = nil
+	l.Unlock()
+
+	results := l.batch(ctx, keys)
+
+	l.Lock()
+	defer l.Unlock()
+
+	for _, key := range keys {
+		call := l.cache[key]
+		call.result = results[key]
+		close(call.done)
+	}
+}
+
+// eachConcurrently is a batch function for lookups without a batch endpoint:
+// the unique keys are fetched concurrently.
+func eachConcurrently[K comparable, V any](fetch func(ctx context.Context, key K) (V, error)) batchFunc[K, V] {
+	return func(ctx context.Context, keys []K) map[K]loaderResult[V] {
+		var (
+			mu      sync.Mutex
+			wg      sync.WaitGroup
+			results = make(map[K]loaderResult[V], len(keys))
+		)
+
+		for _, key := range keys {
+			wg.Add(1)
+			go func(key K) {
+				defer wg.Done()
+
+				v, err := fetch(ctx, key)
+
+				mu.Lock()
+				results[key] = loaderResult[V]{value: v, err: err}
+				mu.Unlock()
+			}(key)
+		}
+
+		wg.Wait()
+		return results
+	}
+}
@@ -0,0 +1,539 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/deadloct/immutablex-go-lib/utils"
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+func pageSize(first *int32) int {
+	if first == nil || *first <= 0 {
+		return DefaultPageSize
+	}
+
+	if *first > MaxPageSize {
+		return MaxPageSize
+	}
+
+	return int(*first)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+type pageInfoResolver struct {
+	endCursor   string
+	hasNextPage bool
+}
+
+func newPageInfo(cursor string, count, pageSize int) *pageInfoResolver {
+	return &pageInfoResolver{endCursor: cursor, hasNextPage: count >= pageSize}
+}
+
+func (r *pageInfoResolver) EndCursor() *string { return nonEmpty(r.endCursor) }
+func (r *pageInfoResolver) HasNextPage() bool  { return r.hasNextPage }
+
+type queryResolver struct {
+	cfg Config
+}
+
+func (q *queryResolver) Collection(ctx context.Context, args struct{ Address string }) (*collectionResolver, error) {
+	collection, err := getLoaders(ctx, q.cfg).collections.Load(ctx, args.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &collectionResolver{cfg: q.cfg, collection: collection}, nil
+}
+
+func (q *queryResolver) Collections(ctx context.Context, args struct {
+	Keyword *string
+	First   *int32
+	After   *string
+}) (*collectionConnectionResolver, error) {
+	cfg := collections.ListCollectionsConfig{
+		Keyword:  deref(args.Keyword),
+		Cursor:   deref(args.After),
+		PageSize: pageSize(args.First),
+	}
+
+	result, err := q.cfg.Collections.ListCollections(ctx, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*collectionResolver, len(result))
+	for i := range result {
+		nodes[i] = &collectionResolver{cfg: q.cfg, collection: &result[i]}
+	}
+
+	return &collectionConnectionResolver{nodes: nodes, pageInfo: newPageInfo(cfg.Cursor, len(result), cfg.PageSize)}, nil
+}
+
+func (q *queryResolver) Asset(ctx context.Context, args struct {
+	Collection string
+	TokenID    string
+}) (*assetResolver, error) {
+	asset, err := q.cfg.Assets.GetAsset(ctx, args.Collection, args.TokenID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAssetResolver(q.cfg, asset), nil
+}
+
+type assetsArgs struct {
+	User     *string
+	Status   *string
+	Name     *string
+	Metadata *string
+	First    *int32
+	After    *string
+}
+
+func (q *queryResolver) Assets(ctx context.Context, args struct {
+	Collection *string
+	User       *string
+	Status     *string
+	Name       *string
+	Metadata   *string
+	First      *int32
+	After      *string
+}) (*assetConnectionResolver, error) {
+	return listAssets(ctx, q.cfg, deref(args.Collection), assetsArgs{
+		User:     args.User,
+		Status:   args.Status,
+		Name:     args.Name,
+		Metadata: args.Metadata,
+		First:    args.First,
+		After:    args.After,
+	})
+}
+
+func (q *queryResolver) Order(ctx context.Context, args struct{ ID gql.ID }) (*orderResolver, error) {
+	order, err := q.cfg.Orders.GetOrder(ctx, string(args.ID), true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &orderResolver{cfg: q.cfg, order: *order}, nil
+}
+
+func (q *queryResolver) Orders(ctx context.Context, args struct {
+	SellTokenAddress *string
+	SellTokenID      *string
+	BuyTokenAddress  *string
+	Status           *string
+	User             *string
+	First            *int32
+	After            *string
+}) (*orderConnectionResolver, error) {
+	return listOrders(ctx, q.cfg, orders.ListOrdersConfig{
+		SellTokenAddress: deref(args.SellTokenAddress),
+		SellTokenID:      deref(args.SellTokenID),
+		BuyTokenAddress:  deref(args.BuyTokenAddress),
+		Status:           deref(args.Status),
+		User:             deref(args.User),
+		Cursor:           deref(args.After),
+		PageSize:         pageSize(args.First),
+	})
+}
+
+func (q *queryResolver) Price(ctx context.Context, args struct {
+	Crypto string
+	Fiat   *string
+}) (*priceResolver, error) {
+	fiat := coinbase.FiatUSD
+	if args.Fiat != nil {
+		var err error
+		if fiat, err = coinbase.NewFiatSymbol(*args.Fiat); err != nil {
+			return nil, err
+		}
+	}
+
+	pair := coinbase.SpotPair{Crypto: coinbase.CryptoSymbol(strings.ToUpper(args.Crypto)), Fiat: fiat}
+	amount, err := getLoaders(ctx, q.cfg).prices.Load(ctx, pair)
+	if err != nil {
+		return nil, err
+	}
+
+	return &priceResolver{pair: pair, amount: amount}, nil
+}
+
+func listAssets(ctx context.Context, c Config, collection string, args assetsArgs) (*assetConnectionResolver, error) {
+	cfg := assets.ListAssetsConfig{
+		Collection: collection,
+		User:       deref(args.User),
+		Status:     deref(args.Status),
+		Name:       deref(args.Name),
+		Metadata:   deref(args.Metadata),
+		Cursor:     deref(args.After),
+		PageSize:   pageSize(args.First),
+	}
+
+	result, err := assets.ListAssetsPage(ctx, c.Assets, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*assetResolver, len(result))
+	for i := range result {
+		nodes[i] = newAssetWithOrdersResolver(c, &result[i])
+	}
+
+	return &assetConnectionResolver{nodes: nodes, pageInfo: newPageInfo(cfg.Cursor, len(result), cfg.PageSize)}, nil
+}
+
+func listOrders(ctx context.Context, c Config, cfg orders.ListOrdersConfig) (*orderConnectionResolver, error) {
+	cfg.IncludeFees = true
+
+	result, err := c.Orders.ListOrders(ctx, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*orderResolver, len(result))
+	for i := range result {
+		nodes[i] = &orderResolver{cfg: c, order: result[i]}
+	}
+
+	return &orderConnectionResolver{nodes: nodes, pageInfo: newPageInfo(cfg.Cursor, len(result), cfg.PageSize)}, nil
+}
+
+type collectionResolver struct {
+	cfg        Config
+	collection *api.Collection
+}
+
+func (r *collectionResolver) Address() string      { return r.collection.Address }
+func (r *collectionResolver) Name() string         { return r.collection.Name }
+func (r *collectionResolver) Description() *string { return r.collection.Description.Get() }
+func (r *collectionResolver) IconURL() *string     { return r.collection.IconUrl.Get() }
+func (r *collectionResolver) ImageURL() *string    { return r.collection.CollectionImageUrl.Get() }
+func (r *collectionResolver) ProjectID() int32     { return r.collection.ProjectId }
+func (r *collectionResolver) UpdatedAt() *string   { return r.collection.UpdatedAt.Get() }
+func (r *collectionResolver) ImmutascanURL() string {
+	return utils.ImmutascanURL + "/address/" + r.collection.Address
+}
+
+func (r *collectionResolver) Assets(ctx context.Context, args assetsArgs) (*assetConnectionResolver, error) {
+	return listAssets(ctx, r.cfg, r.collection.Address, args)
+}
+
+type collectionConnectionResolver struct {
+	nodes    []*collectionResolver
+	pageInfo *pageInfoResolver
+}
+
+func (r *collectionConnectionResolver) Nodes() []*collectionResolver { return r.nodes }
+func (r *collectionConnectionResolver) PageInfo() *pageInfoResolver  { return r.pageInfo }
+
+// assetResolver serves both api.Asset and api.AssetWithOrders, which share the
+// fields exposed in the schema.
+type assetResolver struct {
+	cfg Config
+
+	id           *string
+	tokenID      string
+	tokenAddress string
+	name         *string
+	description  *string
+	status       string
+	user         string
+	imageURL     *string
+	metadata     map[string]interface{}
+	updatedAt    *string
+}
+
+func newAssetResolver(cfg Config, a *api.Asset) *assetResolver {
+	return &assetResolver{
+		cfg:          cfg,
+		id:           a.Id,
+		tokenID:      a.TokenId,
+		tokenAddress: a.TokenAddress,
+		name:         a.Name.Get(),
+		description:  a.Description.Get(),
+		status:       a.Status,
+		user:         a.User,
+		imageURL:     a.ImageUrl.Get(),
+		metadata:     a.Metadata,
+		updatedAt:    a.UpdatedAt.Get(),
+	}
+}
+
+func newAssetWithOrdersResolver(cfg Config, a *api.AssetWithOrders) *assetResolver {
+	return &assetResolver{
+		cfg:          cfg,
+		id:           a.Id,
+		tokenID:      a.TokenId,
+		tokenAddress: a.TokenAddress,
+		name:         a.Name.Get(),
+		description:  a.Description.Get(),
+		status:       a.Status,
+		user:         a.User,
+		imageURL:     a.ImageUrl.Get(),
+		metadata:     a.Metadata,
+		updatedAt:    a.UpdatedAt.Get(),
+	}
+}
+
+func (r *assetResolver) ID() *gql.ID {
+	if r.id == nil {
+		return nil
+	}
+
+	id := gql.ID(*r.id)
+	return &id
+}
+
+func (r *assetResolver) TokenID() string      { return r.tokenID }
+func (r *assetResolver) TokenAddress() string { return r.tokenAddress }
+func (r *assetResolver) Name() *string        { return r.name }
+func (r *assetResolver) Description() *string { return r.description }
+func (r *assetResolver) Status() string       { return r.status }
+func (r *assetResolver) User() string         { return r.user }
+func (r *assetResolver) ImageURL() *string    { return r.imageURL }
+func (r *assetResolver) UpdatedAt() *string   { return r.updatedAt }
+
+func (r *assetResolver) ImmutascanURL() string {
+	return strings.Join([]string{utils.ImmutascanURL, "address", r.tokenAddress, r.tokenID}, "/")
+}
+
+func (r *assetResolver) Metadata() (*string, error) {
+	if r.metadata == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(r.metadata)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode metadata: %w", err)
+	}
+
+	s := string(data)
+	return &s, nil
+}
+
+func (r *assetResolver) Collection(ctx context.Context) (*collectionResolver, error) {
+	collection, err := getLoaders(ctx, r.cfg).collections.Load(ctx, r.tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return &collectionResolver{cfg: r.cfg, collection: collection}, nil
+}
+
+func (r *assetResolver) SellOrders(ctx context.Context, args struct {
+	Status string
+	First  *int32
+	After  *string
+}) (*orderConnectionResolver, error) {
+	if args.After == nil {
+		key := sellOrdersKey{tokenAddress: r.tokenAddress, tokenID: r.tokenID, status: args.Status, first: pageSize(args.First)}
+		return getLoaders(ctx, r.cfg).sellOrders.Load(ctx, key)
+	}
+
+	return listOrders(ctx, r.cfg, orders.ListOrdersConfig{
+		SellTokenAddress: r.tokenAddress,
+		SellTokenID:      r.tokenID,
+		Status:           args.Status,
+		Cursor:           *args.After,
+		PageSize:         pageSize(args.First),
+	})
+}
+
+const (
+	// maxBatchOrders caps the orders fetched for one collection when batching
+	// sell orders. Collections with more fall back to one request per asset.
+	maxBatchOrders = 1000
+
+	// minBatchAssets is the fewest assets of a collection whose sell orders are
+	// fetched collection-wide. Fewer are cheaper to fetch one by one, and a
+	// collection with more than maxBatchOrders active orders costs a single
+	// extra request on top of them.
+	minBatchAssets = 3
+)
+
+// sellOrdersKey is the first page of an asset's sell orders.
+type sellOrdersKey struct {
+	tokenAddress string
+	tokenID      string
+	status       string
+	first        int
+}
+
+// batchSellOrders loads the first page of sell orders for a list of assets
+// with one ListOrders call per collection, grouping the orders by token ID.
+// Collections with fewer than minBatchAssets assets requested are fetched per
+// asset.
+// Assets with more orders than fit on their page are fetched on their own so
+// that their cursor can be used to continue.
+func batchSellOrders(c Config) batchFunc[sellOrdersKey, *orderConnectionResolver] {
+	single := eachConcurrently(func(ctx context.Context, key sellOrdersKey) (*orderConnectionResolver, error) {
+		return listOrders(ctx, c, orders.ListOrdersConfig{
+			SellTokenAddress: key.tokenAddress,
+			SellTokenID:      key.tokenID,
+			Status:           key.status,
+			PageSize:         key.first,
+		})
+	})
+
+	return func(ctx context.Context, keys []sellOrdersKey) map[sellOrdersKey]loaderResult[*orderConnectionResolver] {
+		type group struct{ tokenAddress, status string }
+		groups := make(map[group][]sellOrdersKey)
+		for _, key := range keys {
+			g := group{strings.ToLower(key.tokenAddress), key.status}
+			groups[g] = append(groups[g], key)
+		}
+
+		results := make(map[sellOrdersKey]loaderResult[*orderConnectionResolver], len(keys))
+		var separate []sellOrdersKey
+		for g, keys := range groups {
+			if len(keys) < minBatchAssets {
+				separate = append(separate, keys...)
+				continue
+			}
+
+			cfg := orders.ListOrdersConfig{SellTokenAddress: g.tokenAddress, Status: g.status, IncludeFees: true, PageSize: maxBatchOrders}
+			all, err := c.Orders.ListOrders(ctx, &cfg)
+			if err != nil {
+				for _, key := range keys {
+					results[key] = loaderResult[*orderConnectionResolver]{err: err}
+				}
+				continue
+			}
+
+			if len(all) >= maxBatchOrders {
+				separate = append(separate, keys...)
+				continue
+			}
+
+			byToken := make(map[string][]api.Order)
+			for _, o := range all {
+				id := deref(o.GetSell().Data.TokenId)
+				byToken[id] = append(byToken[id], o)
+			}
+
+			for _, key := range keys {
+				found := byToken[key.tokenID]
+				if len(found) > key.first {
+					separate = append(separate, key)
+					continue
+				}
+
+				nodes := make([]*orderResolver, len(found))
+				for i := range found {
+					nodes[i] = &orderResolver{cfg: c, order: found[i]}
+				}
+
+				results[key] = loaderResult[*orderConnectionResolver]{value: &orderConnectionResolver{nodes: nodes, pageInfo: &pageInfoResolver{}}}
+			}
+		}
+
+		for key, result := range single(ctx, separate) {
+			results[key] = result
+		}
+
+		return results
+	}
+}
+
+type assetConnectionResolver struct {
+	nodes    []*assetResolver
+	pageInfo *pageInfoResolver
+}
+
+func (r *assetConnectionResolver) Nodes() []*assetResolver     { return r.nodes }
+func (r *assetConnectionResolver) PageInfo() *pageInfoResolver { return r.pageInfo }
+
+type orderResolver struct {
+	cfg   Config
+	order api.Order
+}
+
+func (r *orderResolver) OrderID() int32            { return r.order.OrderId }
+func (r *orderResolver) Status() string            { return r.order.Status }
+func (r *orderResolver) User() string              { return r.order.User }
+func (r *orderResolver) Price() float64            { return orders.GetPrice(r.order) }
+func (r *orderResolver) Currency() string          { return string(orders.CurrencySymbol(r.order)) }
+func (r *orderResolver) Timestamp() *string        { return r.order.Timestamp.Get() }
+func (r *orderResolver) UpdatedTimestamp() *string { return r.order.UpdatedTimestamp.Get() }
+func (r *orderResolver) ImmutascanURL() string {
+	return fmt.Sprintf("%s/order/%d", utils.ImmutascanURL, r.order.OrderId)
+}
+func (r *orderResolver) SellTokenAddress() *string { return r.order.GetSell().Data.TokenAddress }
+func (r *orderResolver) SellTokenID() *string      { return r.order.GetSell().Data.TokenId }
+
+func (r *orderResolver) FiatPrice(ctx context.Context, args struct{ Fiat string }) (float64, error) {
+	fiat, err := coinbase.NewFiatSymbol(args.Fiat)
+	if err != nil {
+		return 0, err
+	}
+
+	pair := coinbase.SpotPair{Crypto: orders.CurrencySymbol(r.order), Fiat: fiat}
+	spot, err := getLoaders(ctx, r.cfg).prices.Load(ctx, pair)
+	if err != nil {
+		return 0, err
+	}
+
+	return orders.GetPrice(r.order) * spot, nil
+}
+
+func (r *orderResolver) FormattedFiatPrice(ctx context.Context, args struct {
+	Fiat   string
+	Locale string
+}) (string, error) {
+	amount, err := r.FiatPrice(ctx, struct{ Fiat string }{args.Fiat})
+	if err != nil {
+		return "", err
+	}
+
+	return coinbase.FormatFiat(amount, coinbase.FiatSymbol(strings.ToUpper(args.Fiat)), args.Locale), nil
+}
+
+type orderConnectionResolver struct {
+	nodes    []*orderResolver
+	pageInfo *pageInfoResolver
+}
+
+func (r *orderConnectionResolver) Nodes() []*orderResolver     { return r.nodes }
+func (r *orderConnectionResolver) PageInfo() *pageInfoResolver { return r.pageInfo }
+
+type priceResolver struct {
+	pair   coinbase.SpotPair
+	amount float64
+}
+
+func (r *priceResolver) Crypto() string  { return string(r.pair.Crypto) }
+func (r *priceResolver) Fiat() string    { return string(r.pair.Fiat) }
+func (r *priceResolver) Amount() float64 { return r.amount }
+
+func (r *priceResolver) Formatted(args struct{ Locale string }) string {
+	return coinbase.FormatFiat(r.amount, r.pair.Fiat, args.Locale)
+}
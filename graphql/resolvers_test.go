@@ -0,0 +1,158 @@
+package graphql_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/graphql"
+	"github.com/deadloct/immutablex-go-lib/imxtest"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+// usdcOrder is a hero listed for 40 USDC.
+const usdcOrder = `{
+	"order_id": 10,
+	"status": "active",
+	"user": "0x2222222222222222222222222222222222222222",
+	"sell": {"type": "ERC721", "data": {"token_address": "0x6465ef3009f3c474774f4afb607a5d600ea71d95", "token_id": "4", "quantity": "1"}},
+	"buy": {"type": "ERC20", "data": {"token_address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "decimals": 6, "symbol": "USDC", "quantity": "40000000", "quantity_with_fees": "41000000"}}
+}`
+
+// query serves query with h and decodes its data into v.
+func query(t *testing.T, h http.Handler, query string, v interface{}) {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]string{"query": query})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+
+	var resp struct {
+		Data   json.RawMessage
+		Errors []interface{}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Errors) > 0 {
+		t.Fatal(resp.Errors)
+	}
+
+	if err := json.Unmarshal(resp.Data, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSellOrdersAreBatchedPerCollection(t *testing.T) {
+	f := imxtest.DefaultFixtures()
+	ordersClient := imxtest.NewOrdersClient(f)
+
+	h, err := graphql.NewHandler(graphql.Config{
+		Assets:      imxtest.NewAssetsClient(f),
+		Collections: imxtest.NewCollectionsClient(f),
+		Orders:      ordersClient,
+		Prices:      imxtest.NewPriceClient(nil),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var data struct {
+		Assets struct {
+			Nodes []struct {
+				TokenID    string
+				SellOrders struct {
+					Nodes []struct{ OrderID int32 }
+				}
+			}
+		}
+	}
+	query(t, h, `{
+		assets(first: 10) {
+			nodes {
+				tokenId
+				sellOrders { nodes { orderId } }
+			}
+		}
+	}`, &data)
+
+	got := make(map[string][]int32)
+	for _, a := range data.Assets.Nodes {
+		for _, o := range a.SellOrders.Nodes {
+			got[a.TokenID] = append(got[a.TokenID], o.OrderID)
+		}
+	}
+
+	want := map[string][]int32{"1": {1}, "2": {2}, "6": {5}}
+	if len(got) != len(want) {
+		t.Errorf("got active orders %v, want %v", got, want)
+	}
+	for id, ids := range want {
+		if len(got[id]) != 1 || got[id][0] != ids[0] {
+			t.Errorf("token %s: got orders %v, want %v", id, got[id], ids)
+		}
+	}
+
+	// The four heroes share one request, the two portals are too few to batch.
+	calls := ordersClient.CallsTo("ListOrders")
+	if len(calls) != 3 {
+		t.Fatalf("ListOrders called %d times for %d assets, want 3", len(calls), len(data.Assets.Nodes))
+	}
+
+	batched := 0
+	for _, call := range calls {
+		if cfg := call.Args[0].(orders.ListOrdersConfig); cfg.SellTokenID == "" {
+			batched++
+		}
+	}
+	if batched != 1 {
+		t.Errorf("got %d collection-wide requests, want 1", batched)
+	}
+}
+
+func TestERC20OrderCurrency(t *testing.T) {
+	f := imxtest.DefaultFixtures()
+
+	var usdc api.Order
+	if err := json.Unmarshal([]byte(usdcOrder), &usdc); err != nil {
+		t.Fatal(err)
+	}
+	f.Orders = append(f.Orders, usdc)
+
+	h, err := graphql.NewHandler(graphql.Config{
+		Assets:      imxtest.NewAssetsClient(f),
+		Collections: imxtest.NewCollectionsClient(f),
+		Orders:      imxtest.NewOrdersClient(f),
+		Prices: imxtest.NewPriceClient(map[coinbase.SpotPair]float64{
+			{Crypto: coinbase.CryptoETH, Fiat: coinbase.FiatEUR}:  2000,
+			{Crypto: coinbase.CryptoUSDC, Fiat: coinbase.FiatEUR}: 0.5,
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var data struct {
+		Eth, Usdc struct {
+			Currency  string
+			Price     float64
+			FiatPrice float64
+		}
+	}
+	query(t, h, `{
+		eth: order(id: "1") { currency price fiatPrice(fiat: "eur") }
+		usdc: order(id: "10") { currency price fiatPrice(fiat: "eur") }
+	}`, &data)
+
+	if data.Eth.Currency != "ETH" || data.Eth.FiatPrice != data.Eth.Price*2000 {
+		t.Errorf("got ETH order %+v", data.Eth)
+	}
+
+	if data.Usdc.Currency != "USDC" || data.Usdc.Price != 41 || data.Usdc.FiatPrice != 20.5 {
+		t.Errorf("got USDC order %+v, want USDC 41 costing 20.5 EUR", data.Usdc)
+	}
+}
@@ -0,0 +1,92 @@
+package graphql
+
+// Schema is the GraphQL schema served over the assets, collections and orders
+// clients. Connections page through the IMX API: "first" is the page size and
+// "after" the IMX cursor returned in pageInfo.endCursor.
+const Schema = `
+schema {
+	query: Query
+}
+
+type Query {
+	collection(address: String!): Collection
+	collections(keyword: String, first: Int, after: String): CollectionConnection!
+	asset(collection: String!, tokenId: String!): Asset
+	assets(collection: String, user: String, status: String, name: String, metadata: String, first: Int, after: String): AssetConnection!
+	order(id: ID!): Order
+	orders(sellTokenAddress: String, sellTokenId: String, buyTokenAddress: String, status: String, user: String, first: Int, after: String): OrderConnection!
+	price(crypto: String!, fiat: String): Price!
+}
+
+type PageInfo {
+	endCursor: String
+	hasNextPage: Boolean!
+}
+
+type Collection {
+	address: String!
+	name: String!
+	description: String
+	iconUrl: String
+	imageUrl: String
+	projectId: Int!
+	updatedAt: String
+	immutascanUrl: String!
+	assets(user: String, status: String, name: String, metadata: String, first: Int, after: String): AssetConnection!
+}
+
+type CollectionConnection {
+	nodes: [Collection!]!
+	pageInfo: PageInfo!
+}
+
+type Asset {
+	id: ID
+	tokenId: String!
+	tokenAddress: String!
+	name: String
+	description: String
+	status: String!
+	user: String!
+	imageUrl: String
+	# Asset metadata encoded as JSON.
+	metadata: String
+	updatedAt: String
+	immutascanUrl: String!
+	collection: Collection
+	sellOrders(status: String = "active", first: Int, after: String): OrderConnection!
+}
+
+type AssetConnection {
+	nodes: [Asset!]!
+	pageInfo: PageInfo!
+}
+
+type Order {
+	orderId: Int!
+	status: String!
+	user: String!
+	sellTokenAddress: String
+	sellTokenId: String
+	# Buy price including fees, in units of currency.
+	price: Float!
+	currency: String!
+	fiatPrice(fiat: String = "USD"): Float!
+	formattedFiatPrice(fiat: String = "USD", locale: String = "en-US"): String!
+	timestamp: String
+	updatedTimestamp: String
+	immutascanUrl: String!
+}
+
+type OrderConnection {
+	nodes: [Order!]!
+	pageInfo: PageInfo!
+}
+
+type Price {
+	crypto: String!
+	fiat: String!
+	amount: Float!
+	formatted(locale: String = "en-US"): String!
+}
+`
@@ -1,6 +1,7 @@
 package imx
 
 import (
+	"net/http"
 	"sync"
 
 	"github.com/immutable/imx-core-sdk-golang/imx"
@@ -14,8 +15,9 @@ type ClientWrapper interface {
 }
 
 type Client struct {
-	key       string
-	imxClient *imx.Client
+	key        string
+	httpClient *http.Client
+	imxClient  *imx.Client
 
 	sync.Mutex
 }
@@ -24,14 +26,25 @@ func NewClient(alchemyKey string) *Client {
 	return &Client{key: alchemyKey}
 }
 
+// NewClientWithHTTPClient is NewClient with the HTTP client the SDK sends
+// API requests through, e.g. to record them. A nil client uses the SDK's.
+func NewClientWithHTTPClient(alchemyKey string, httpClient *http.Client) *Client {
+	return &Client{key: alchemyKey, httpClient: httpClient}
+}
+
 func (c *Client) Start() error {
 	if c.imxClient != nil {
 		return nil
 	}
 
+	apiCfg := api.NewConfiguration()
+	if c.httpClient != nil {
+		apiCfg.HTTPClient = c.httpClient
+	}
+
 	cfg := imx.Config{
 		AlchemyAPIKey: c.key,
-		APIConfig:     api.NewConfiguration(),
+		APIConfig:     apiCfg,
 		Environment:   imx.Mainnet,
 	}
 
@@ -0,0 +1,284 @@
+package imxtest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/imxtest"
+	"github.com/deadloct/immutablex-go-lib/orders"
+)
+
+// backends are the REST and Alchemy clients, both talking to the same fake
+// server so every scenario must give identical results and send identical
+// requests.
+type backends struct {
+	srv         *imxtest.Server
+	assets      map[string]assets.Client
+	collections map[string]collections.Client
+	orders      map[string]orders.Client
+}
+
+func newBackends(t *testing.T) *backends {
+	t.Helper()
+
+	srv := imxtest.NewServer(imxtest.DefaultFixtures())
+	t.Cleanup(srv.Close)
+
+	sdk, err := imxtest.NewSDKClient(srv)
+	if err != nil {
+		t.Fatalf("could not create sdk client: %v", err)
+	}
+
+	b := &backends{
+		srv: srv,
+		assets: map[string]assets.Client{
+			"rest":    assets.NewRESTClient(srv.AssetsConfig()),
+			"alchemy": assets.NewAlchemyClient(assets.AlchemyClientConfig{Client: sdk}),
+		},
+		collections: map[string]collections.Client{
+			"rest":    collections.NewRESTClient(srv.CollectionsConfig()),
+			"alchemy": collections.NewAlchemyClient(collections.AlchemyClientConfig{Client: sdk}),
+		},
+		orders: map[string]orders.Client{
+			"rest":    orders.NewRESTClient(srv.OrdersConfig()),
+			"alchemy": orders.NewAlchemyClient(orders.AlchemyClientConfig{Client: sdk, URL: srv.URL}),
+		},
+	}
+
+	return b
+}
+
+type outcome struct {
+	Result   interface{}
+	Failed   bool
+	Requests []imxtest.Request
+}
+
+// compare runs fn against each backend, fails if their outcomes differ and
+// returns the REST result. Errors are only compared by whether they
+// happened, the SDK wraps them differently.
+func compare[C any](t *testing.T, srv *imxtest.Server, clients map[string]C, fn func(C) (interface{}, error)) interface{} {
+	t.Helper()
+
+	outcomes := make(map[string]outcome)
+	for _, name := range []string{"rest", "alchemy"} {
+		before := len(srv.Requests())
+		result, err := fn(clients[name])
+
+		o := outcome{Result: result, Requests: srv.Requests()[before:]}
+		if err != nil {
+			o.Result = nil
+			o.Failed = true
+		}
+		outcomes[name] = o
+	}
+
+	rest, alchemy := outcomes["rest"], outcomes["alchemy"]
+	if rest.Failed != alchemy.Failed {
+		t.Errorf("errors differ: rest failed %t, alchemy failed %t", rest.Failed, alchemy.Failed)
+	}
+
+	if !reflect.DeepEqual(rest.Requests, alchemy.Requests) {
+		t.Errorf("requests differ:\nrest    %+v\nalchemy %+v", rest.Requests, alchemy.Requests)
+	}
+
+	if a, b := asJSON(t, rest.Result), asJSON(t, alchemy.Result); a != b {
+		t.Errorf("results differ:\nrest    %s\nalchemy %s", a, b)
+	}
+
+	return rest.Result
+}
+
+// checkLen fails unless result is a slice of want items.
+func checkLen(t *testing.T, result interface{}, want int) {
+	t.Helper()
+
+	v := reflect.ValueOf(result)
+	if v.Kind() != reflect.Slice {
+		t.Errorf("got %T, want %d items", result, want)
+		return
+	}
+
+	if v.Len() != want {
+		t.Errorf("got %d items, want %d", v.Len(), want)
+	}
+}
+
+func asJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("could not encode result: %v", err)
+	}
+
+	return string(b)
+}
+
+func TestAssetsConformance(t *testing.T) {
+	ctx := context.Background()
+
+	scenarios := map[string]struct {
+		want int
+		cfg  assets.ListAssetsConfig
+	}{
+		"all":              {6, assets.ListAssetsConfig{}},
+		"collection":       {4, assets.ListAssetsConfig{Collection: "hero"}},
+		"page":             {3, assets.ListAssetsConfig{Collection: "hero", PageSize: 3}},
+		"name":             {2, assets.ListAssetsConfig{Name: "portal"}},
+		"metadata":         {2, assets.ListAssetsConfig{Metadata: `{"Rarity":["Epic"]}`}},
+		"status":           {1, assets.ListAssetsConfig{Status: "burned"}},
+		"user":             {3, assets.ListAssetsConfig{User: "0x2222222222222222222222222222222222222222"}},
+		"order by name":    {6, assets.ListAssetsConfig{OrderBy: "name", Direction: "asc"}},
+		"updated range":    {3, assets.ListAssetsConfig{UpdatedMinTimestamp: "2022-03-02T00:00:00Z", UpdatedMaxTimestamp: "2022-03-04T00:00:00Z"}},
+		"include fees":     {6, assets.ListAssetsConfig{IncludeFees: true, SellOrders: true, BuyOrders: true}},
+		"small pages":      {1, assets.ListAssetsConfig{PageSize: 1}},
+		"more than exists": {6, assets.ListAssetsConfig{PageSize: 50}},
+	}
+
+	for name, scenario := range scenarios {
+		scenario := scenario
+		t.Run(name, func(t *testing.T) {
+			b := newBackends(t)
+			result := compare(t, b.srv, b.assets, func(c assets.Client) (interface{}, error) {
+				return c.ListAssets(ctx, scenario.cfg)
+			})
+			checkLen(t, result, scenario.want)
+		})
+	}
+
+	t.Run("continue from cursor", func(t *testing.T) {
+		b := newBackends(t)
+		result := compare(t, b.srv, b.assets, func(c assets.Client) (interface{}, error) {
+			cfg := assets.ListAssetsConfig{PageSize: 2}
+			if _, err := assets.ListAssetsPage(ctx, c, &cfg); err != nil {
+				return nil, err
+			}
+
+			cfg.Assets = nil
+			return assets.ListAssetsPage(ctx, c, &cfg)
+		})
+		checkLen(t, result, 2)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		b := newBackends(t)
+		compare(t, b.srv, b.assets, func(c assets.Client) (interface{}, error) {
+			return c.GetAsset(ctx, "hero", "2", false)
+		})
+	})
+
+	t.Run("get missing", func(t *testing.T) {
+		b := newBackends(t)
+		compare(t, b.srv, b.assets, func(c assets.Client) (interface{}, error) {
+			return c.GetAsset(ctx, "hero", "99", false)
+		})
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		b := newBackends(t)
+		b.srv.InjectFault(imxtest.Fault{Status: http.StatusServiceUnavailable})
+		compare(t, b.srv, b.assets, func(c assets.Client) (interface{}, error) {
+			return c.ListAssets(ctx, assets.ListAssetsConfig{})
+		})
+	})
+}
+
+func TestCollectionsConformance(t *testing.T) {
+	ctx := context.Background()
+
+	scenarios := map[string]struct {
+		want int
+		cfg  collections.ListCollectionsConfig
+	}{
+		"all":       {2, collections.ListCollectionsConfig{}},
+		"keyword":   {1, collections.ListCollectionsConfig{Keyword: "portal"}},
+		"whitelist": {1, collections.ListCollectionsConfig{Whitelist: "0x6465ef3009f3c474774f4afb607a5d600ea71d95"}},
+		"blacklist": {1, collections.ListCollectionsConfig{Blacklist: "0x6465ef3009f3c474774f4afb607a5d600ea71d95"}},
+		"shortcuts": {1, collections.ListCollectionsConfig{Whitelist: "hero, portal", Blacklist: "portal"}},
+		"order by":  {2, collections.ListCollectionsConfig{OrderBy: "name", Direction: "desc"}},
+		"page":      {1, collections.ListCollectionsConfig{PageSize: 1}},
+	}
+
+	for name, scenario := range scenarios {
+		scenario := scenario
+		t.Run(name, func(t *testing.T) {
+			b := newBackends(t)
+			result := compare(t, b.srv, b.collections, func(c collections.Client) (interface{}, error) {
+				cfg := scenario.cfg
+				return c.ListCollections(ctx, &cfg)
+			})
+			checkLen(t, result, scenario.want)
+		})
+	}
+
+	t.Run("get", func(t *testing.T) {
+		b := newBackends(t)
+		compare(t, b.srv, b.collections, func(c collections.Client) (interface{}, error) {
+			return c.GetCollection(ctx, "portal")
+		})
+	})
+
+	t.Run("get missing", func(t *testing.T) {
+		b := newBackends(t)
+		compare(t, b.srv, b.collections, func(c collections.Client) (interface{}, error) {
+			return c.GetCollection(ctx, "0x0000000000000000000000000000000000000000")
+		})
+	})
+}
+
+func TestOrdersConformance(t *testing.T) {
+	ctx := context.Background()
+
+	scenarios := map[string]struct {
+		want int
+		cfg  orders.ListOrdersConfig
+	}{
+		"all":             {5, orders.ListOrdersConfig{}},
+		"status":          {3, orders.ListOrdersConfig{Status: "active"}},
+		"sell collection": {3, orders.ListOrdersConfig{SellTokenAddress: "hero"}},
+		"sell metadata":   {2, orders.ListOrdersConfig{SellMetadata: `{"Rarity":["Epic"]}`}},
+		"cheapest":        {2, orders.ListOrdersConfig{Status: "active", OrderBy: "buy_quantity", Direction: "asc", PageSize: 2}},
+		"user":            {3, orders.ListOrdersConfig{User: "0x1111111111111111111111111111111111111111"}},
+		"small pages":     {1, orders.ListOrdersConfig{PageSize: 1}},
+		"quantity range":  {3, orders.ListOrdersConfig{BuyMinQuantity: "600000000000000000", BuyMaxQuantity: "2000000000000000000"}},
+	}
+
+	for name, scenario := range scenarios {
+		scenario := scenario
+		t.Run(name, func(t *testing.T) {
+			b := newBackends(t)
+			result := compare(t, b.srv, b.orders, func(c orders.Client) (interface{}, error) {
+				cfg := scenario.cfg
+				return c.ListOrders(ctx, &cfg)
+			})
+			checkLen(t, result, scenario.want)
+		})
+	}
+
+	t.Run("continue from cursor", func(t *testing.T) {
+		b := newBackends(t)
+		result := compare(t, b.srv, b.orders, func(c orders.Client) (interface{}, error) {
+			cfg := orders.ListOrdersConfig{PageSize: 2}
+			if _, err := c.ListOrders(ctx, &cfg); err != nil {
+				return nil, err
+			}
+
+			cfg.Orders = nil
+			return c.ListOrders(ctx, &cfg)
+		})
+		checkLen(t, result, 2)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		b := newBackends(t)
+		compare(t, b.srv, b.orders, func(c orders.Client) (interface{}, error) {
+			return c.GetOrder(ctx, "3", false)
+		})
+	})
+}
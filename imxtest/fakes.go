@@ -0,0 +1,427 @@
+package imxtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/collections"
+	libimx "github.com/deadloct/immutablex-go-lib/imx"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/deadloct/immutablex-go-lib/utils"
+	"github.com/immutable/imx-core-sdk-golang/imx"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+var (
+	_ assets.Client        = (*AssetsClient)(nil)
+	_ collections.Client   = (*CollectionsClient)(nil)
+	_ orders.Client        = (*OrdersClient)(nil)
+	_ libimx.ClientWrapper = (*SDKClient)(nil)
+	_ coinbase.PriceClient = (*PriceClient)(nil)
+)
+
+func notFound(format string, args ...interface{}) error {
+	return &utils.APIError{StatusCode: http.StatusNotFound, Code: "not_found", Message: fmt.Sprintf(format, args...)}
+}
+
+func badRequest(err error) error {
+	return &utils.APIError{StatusCode: http.StatusBadRequest, Code: "bad_request", Message: err.Error()}
+}
+
+// fakePage returns the page of items after cursor the way the real clients
+// do: everything when pageSize is 0, otherwise at most pageSize items and a
+// cursor to continue from.
+func fakePage[T any](items []T, cursor string, pageSize int) ([]T, string, error) {
+	offset := 0
+	if cursor != "" {
+		n, err := decodeCursor(cursor)
+		if err != nil || n > len(items) {
+			return nil, "", fmt.Errorf("invalid cursor %q", cursor)
+		}
+		offset = n
+	}
+
+	end := len(items)
+	if pageSize > 0 && offset+pageSize < end {
+		end = offset + pageSize
+	}
+
+	return items[offset:end], encodeCursor(end), nil
+}
+
+// AssetsClient is an in-memory assets.Client. Filters are applied to the
+// seeded assets for Collection, User, Status, Name, Metadata and the updated
+// timestamps, and results are sorted by OrderBy and Direction like Server
+// does, by updated_at when OrderBy is empty like the real clients.
+type AssetsClient struct {
+	Mock
+
+	assets []api.AssetWithOrders
+	data   sync.Mutex
+}
+
+func NewAssetsClient(f *Fixtures) *AssetsClient {
+	c := &AssetsClient{}
+	if f != nil {
+		c.AddAssets(f.Assets...)
+	}
+
+	return c
+}
+
+func (c *AssetsClient) AddAssets(a ...api.AssetWithOrders) {
+	c.data.Lock()
+	defer c.data.Unlock()
+	c.assets = append(c.assets, a...)
+}
+
+func (c *AssetsClient) Start() error { return c.record("Start") }
+
+func (c *AssetsClient) Stop() { c.record("Stop") }
+
+func (c *AssetsClient) GetAsset(ctx context.Context, tokenAddress, tokenID string, includeFees bool) (*api.Asset, error) {
+	if err := c.record("GetAsset", tokenAddress, tokenID, includeFees); err != nil {
+		return nil, err
+	}
+
+	tokenAddress = collections.ResolveCollection("token address", tokenAddress)
+
+	c.data.Lock()
+	defer c.data.Unlock()
+
+	for _, a := range c.assets {
+		if strings.EqualFold(a.TokenAddress, tokenAddress) && a.TokenId == tokenID {
+			result := asset(a)
+			return &result, nil
+		}
+	}
+
+	return nil, notFound("asset %s/%s not found", tokenAddress, tokenID)
+}
+
+func (c *AssetsClient) ListAssets(ctx context.Context, cfg assets.ListAssetsConfig) ([]api.AssetWithOrders, error) {
+	if err := c.record("ListAssets", cfg); err != nil {
+		return nil, err
+	}
+
+	return c.listAssets(&cfg)
+}
+
+func (c *AssetsClient) ListAssetsPage(ctx context.Context, cfg *assets.ListAssetsConfig) ([]api.AssetWithOrders, error) {
+	if err := c.record("ListAssetsPage", *cfg); err != nil {
+		return nil, err
+	}
+
+	return c.listAssets(cfg)
+}
+
+func (c *AssetsClient) listAssets(cfg *assets.ListAssetsConfig) ([]api.AssetWithOrders, error) {
+	var metadata map[string][]string
+	if cfg.Metadata != "" {
+		if err := json.Unmarshal([]byte(cfg.Metadata), &metadata); err != nil {
+			return nil, fmt.Errorf("invalid metadata: %w", err)
+		}
+	}
+
+	collection := collections.ResolveCollection("collection", cfg.Collection)
+	user := collections.ResolveWallet("user", cfg.User)
+
+	c.data.Lock()
+	var matched []api.AssetWithOrders
+	for _, a := range c.assets {
+		if matchFold(collection, a.TokenAddress) && matchFold(user, a.User) && matchFold(cfg.Status, a.Status) &&
+			contains(a.GetName(), cfg.Name) && matchMetadata(metadata, a.Metadata) &&
+			inRange(a.GetUpdatedAt(), cfg.UpdatedMinTimestamp, cfg.UpdatedMaxTimestamp) {
+			matched = append(matched, a)
+		}
+	}
+	c.data.Unlock()
+
+	orderBy := cfg.OrderBy
+	if orderBy == "" {
+		orderBy = assets.DefaultOrderBy
+	}
+
+	if err := sortItems(matched, orderBy, cfg.Direction, assetKeys, nil); err != nil {
+		return nil, badRequest(err)
+	}
+
+	result, cursor, err := fakePage(matched, cfg.Cursor, cfg.PageSize-len(cfg.Assets))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Assets = append(cfg.Assets, result...)
+	cfg.Cursor = cursor
+	return cfg.Assets, nil
+}
+
+// CollectionsClient is an in-memory collections.Client. Keyword, Whitelist
+// and Blacklist are applied to the seeded collections, and results are sorted
+// by OrderBy and Direction like Server does.
+type CollectionsClient struct {
+	Mock
+
+	collections []api.Collection
+	data        sync.Mutex
+}
+
+func NewCollectionsClient(f *Fixtures) *CollectionsClient {
+	c := &CollectionsClient{}
+	if f != nil {
+		c.AddCollections(f.Collections...)
+	}
+
+	return c
+}
+
+func (c *CollectionsClient) AddCollections(cols ...api.Collection) {
+	c.data.Lock()
+	defer c.data.Unlock()
+	c.collections = append(c.collections, cols...)
+}
+
+func (c *CollectionsClient) Start() error { return c.record("Start") }
+
+func (c *CollectionsClient) Stop() { c.record("Stop") }
+
+func (c *CollectionsClient) GetCollection(ctx context.Context, collection string) (*api.Collection, error) {
+	if err := c.record("GetCollection", collection); err != nil {
+		return nil, err
+	}
+
+	collection = collections.ResolveCollection("collection", collection)
+
+	c.data.Lock()
+	defer c.data.Unlock()
+
+	for _, col := range c.collections {
+		if strings.EqualFold(col.Address, collection) {
+			result := col
+			return &result, nil
+		}
+	}
+
+	return nil, notFound("collection %s not found", collection)
+}
+
+func (c *CollectionsClient) ListCollections(ctx context.Context, cfg *collections.ListCollectionsConfig) ([]api.Collection, error) {
+	if err := c.record("ListCollections", *cfg); err != nil {
+		return nil, err
+	}
+
+	whitelist := splitList(collections.ResolveCollections("whitelist", cfg.Whitelist))
+	blacklist := splitList(collections.ResolveCollections("blacklist", cfg.Blacklist))
+
+	c.data.Lock()
+	var matched []api.Collection
+	for _, col := range c.collections {
+		addr := strings.ToLower(col.Address)
+		if (len(whitelist) == 0 || whitelist[addr]) && !blacklist[addr] && contains(col.Name, cfg.Keyword) {
+			matched = append(matched, col)
+		}
+	}
+	c.data.Unlock()
+
+	if err := sortItems(matched, cfg.OrderBy, cfg.Direction, collectionKeys, nil); err != nil {
+		return nil, badRequest(err)
+	}
+
+	result, cursor, err := fakePage(matched, cfg.Cursor, cfg.PageSize-len(cfg.Collections))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Collections = append(cfg.Collections, result...)
+	cfg.Cursor = cursor
+	return cfg.Collections, nil
+}
+
+// OrdersClient is an in-memory orders.Client. Status, User, the timestamps
+// and the buy and sell token type, address and ID filters are applied to the
+// seeded orders, and results are sorted by OrderBy and Direction like Server
+// does.
+type OrdersClient struct {
+	Mock
+
+	orders []api.Order
+	data   sync.Mutex
+}
+
+func NewOrdersClient(f *Fixtures) *OrdersClient {
+	c := &OrdersClient{}
+	if f != nil {
+		c.AddOrders(f.Orders...)
+	}
+
+	return c
+}
+
+func (c *OrdersClient) AddOrders(o ...api.Order) {
+	c.data.Lock()
+	defer c.data.Unlock()
+	c.orders = append(c.orders, o...)
+}
+
+func (c *OrdersClient) Start() error { return c.record("Start") }
+
+func (c *OrdersClient) Stop() { c.record("Stop") }
+
+func (c *OrdersClient) GetOrder(ctx context.Context, orderID string, includeFees bool) (*api.Order, error) {
+	if err := c.record("GetOrder", orderID, includeFees); err != nil {
+		return nil, err
+	}
+
+	c.data.Lock()
+	defer c.data.Unlock()
+
+	for _, o := range c.orders {
+		if fmt.Sprint(o.OrderId) == orderID {
+			result := o
+			return &result, nil
+		}
+	}
+
+	return nil, notFound("order %s not found", orderID)
+}
+
+func (c *OrdersClient) ListOrders(ctx context.Context, cfg *orders.ListOrdersConfig) ([]api.Order, error) {
+	if err := c.record("ListOrders", *cfg); err != nil {
+		return nil, err
+	}
+
+	user := collections.ResolveWallet("user", cfg.User)
+	buyAddr := collections.ResolveCollection("buy token address", cfg.BuyTokenAddress)
+	sellAddr := collections.ResolveCollection("sell token address", cfg.SellTokenAddress)
+
+	c.data.Lock()
+	var matched []api.Order
+	for _, o := range c.orders {
+		if matchFold(cfg.Status, o.Status) && matchFold(user, o.User) &&
+			inRange(nullable(o.Timestamp), cfg.MinTimestamp, cfg.MaxTimestamp) &&
+			inRange(o.GetUpdatedTimestamp(), cfg.UpdatedMinTimestamp, cfg.UpdatedMaxTimestamp) &&
+			matchFold(cfg.BuyTokenType, o.Buy.Type) && matchFold(buyAddr, o.Buy.Data.GetTokenAddress()) && matchFold(cfg.BuyTokenID, o.Buy.Data.GetTokenId()) &&
+			matchFold(cfg.SellTokenType, o.Sell.Type) && matchFold(sellAddr, o.Sell.Data.GetTokenAddress()) && matchFold(cfg.SellTokenID, o.Sell.Data.GetTokenId()) {
+			matched = append(matched, o)
+		}
+	}
+	c.data.Unlock()
+
+	if err := sortItems(matched, cfg.OrderBy, cfg.Direction, orderKeys, orderNumericKeys); err != nil {
+		return nil, badRequest(err)
+	}
+
+	result, cursor, err := fakePage(matched, cfg.Cursor, cfg.PageSize-len(cfg.Orders))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Orders = append(cfg.Orders, result...)
+	cfg.Cursor = cursor
+	return cfg.Orders, nil
+}
+
+// SDKClient is an imx.ClientWrapper around an SDK client, usually one
+// talking to a Server. Stop doesn't close the SDK client so it can be reused.
+type SDKClient struct {
+	Mock
+
+	Client *imx.Client
+}
+
+// NewSDKClient returns an SDK client that sends API requests to s.
+func NewSDKClient(s *Server) (*SDKClient, error) {
+	apiCfg := api.NewConfiguration()
+	apiCfg.Servers = api.ServerConfigurations{{URL: s.URL}}
+	apiCfg.HTTPClient = s.Client()
+
+	client, err := imx.NewClient(&imx.Config{
+		AlchemyAPIKey: "imxtest",
+		APIConfig:     apiCfg,
+		Environment:   imx.Mainnet,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SDKClient{Client: client}, nil
+}
+
+func (c *SDKClient) Start() error { return c.record("Start") }
+
+func (c *SDKClient) Stop() { c.record("Stop") }
+
+func (c *SDKClient) GetClient() *imx.Client {
+	c.record("GetClient")
+	return c.Client
+}
+
+// PriceClient is a coinbase.PriceClient returning fixed prices, zero for
+// pairs it doesn't know, like the real client does when a fetch fails.
+//
+//	prices := imxtest.NewPriceClient(map[coinbase.SpotPair]float64{{Crypto: coinbase.CryptoETH, Fiat: coinbase.FiatUSD}: 2000})
+//	renderer := notify.Renderer{Fiat: coinbase.FiatUSD, Prices: prices}
+type PriceClient struct {
+	Mock
+
+	prices map[coinbase.SpotPair]float64
+	data   sync.Mutex
+}
+
+func NewPriceClient(prices map[coinbase.SpotPair]float64) *PriceClient {
+	c := &PriceClient{prices: make(map[coinbase.SpotPair]float64)}
+	for pair, price := range prices {
+		c.SetPrice(pair.Crypto, pair.Fiat, price)
+	}
+
+	return c
+}
+
+func (c *PriceClient) SetPrice(crypto coinbase.CryptoSymbol, fiat coinbase.FiatSymbol, price float64) {
+	c.data.Lock()
+	defer c.data.Unlock()
+	c.prices[coinbase.SpotPair{Crypto: crypto, Fiat: fiat}] = price
+}
+
+func (c *PriceClient) RetrieveSpotPrice(crypto coinbase.CryptoSymbol, fiat coinbase.FiatSymbol) float64 {
+	if err := c.record("RetrieveSpotPrice", crypto, fiat); err != nil {
+		return 0
+	}
+
+	return c.price(coinbase.SpotPair{Crypto: crypto, Fiat: fiat})
+}
+
+func (c *PriceClient) RetrieveSpotPrices(ctx context.Context, pairs []coinbase.SpotPair) map[coinbase.SpotPair]float64 {
+	err := c.record("RetrieveSpotPrices", pairs)
+
+	results := make(map[coinbase.SpotPair]float64, len(pairs))
+	for _, pair := range pairs {
+		if err != nil {
+			results[pair] = 0
+			continue
+		}
+		results[pair] = c.price(pair)
+	}
+
+	return results
+}
+
+func (c *PriceClient) price(pair coinbase.SpotPair) float64 {
+	if pair.Fiat == "" {
+		pair.Fiat = coinbase.FiatUSD
+	}
+
+	if pair.Crypto == "" {
+		pair.Crypto = coinbase.CryptoETH
+	}
+
+	c.data.Lock()
+	defer c.data.Unlock()
+	return c.prices[pair]
+}
@@ -0,0 +1,49 @@
+package imxtest
+
+import (
+	_ "embed"
+	"encoding/json"
+	"os"
+
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+//go:embed fixtures.json
+var defaultFixtures []byte
+
+// Fixtures is the data a Server is seeded with. The JSON layout matches the
+// API's own responses, so a result array can be pasted in as is.
+type Fixtures struct {
+	Assets      []api.AssetWithOrders `json:"assets"`
+	Collections []api.Collection      `json:"collections"`
+	Orders      []api.Order           `json:"orders"`
+}
+
+// DefaultFixtures returns a small set of collections, assets and orders
+// that reference each other.
+func DefaultFixtures() *Fixtures {
+	f, err := ParseFixtures(defaultFixtures)
+	if err != nil {
+		panic(err)
+	}
+
+	return f
+}
+
+func LoadFixtures(path string) (*Fixtures, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseFixtures(b)
+}
+
+func ParseFixtures(b []byte) (*Fixtures, error) {
+	var f Fixtures
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
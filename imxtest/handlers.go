@@ -0,0 +1,460 @@
+package imxtest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	code := strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+	writeJSON(w, status, apiError{Code: code, Message: message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// subPath returns what follows prefix in the request path, split on "/".
+func subPath(r *http.Request, prefix string) []string {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	if rest == "" {
+		return nil
+	}
+
+	return strings.Split(rest, "/")
+}
+
+func (s *Server) handleAssets(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	all := append([]api.AssetWithOrders(nil), s.assets...)
+	s.Unlock()
+
+	switch parts := subPath(r, assets.ListAssetsEndpoint); len(parts) {
+	case 0:
+	case 2:
+		for _, a := range all {
+			if strings.EqualFold(a.TokenAddress, parts[0]) && a.TokenId == parts[1] {
+				writeJSON(w, http.StatusOK, asset(a))
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, fmt.Sprintf("asset %s/%s not found", parts[0], parts[1]))
+		return
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	q := r.URL.Query()
+	var metadata map[string][]string
+	if m := q.Get("metadata"); m != "" {
+		if err := json.Unmarshal([]byte(m), &metadata); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid metadata: "+err.Error())
+			return
+		}
+	}
+
+	var matched []api.AssetWithOrders
+	for _, a := range all {
+		switch {
+		case !matchFold(q.Get("collection"), a.TokenAddress),
+			!matchFold(q.Get("user"), a.User),
+			!matchFold(q.Get("status"), a.Status),
+			!contains(a.GetName(), q.Get("name")),
+			!matchMetadata(metadata, a.Metadata),
+			!inRange(a.GetUpdatedAt(), q.Get("updated_min_timestamp"), q.Get("updated_max_timestamp")):
+			continue
+		}
+		matched = append(matched, a)
+	}
+
+	if !order(w, q, matched, assetKeys, nil) {
+		return
+	}
+
+	result, cursor, remaining, ok := paginate(w, q, matched)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, api.ListAssetsResponse{Cursor: cursor, Remaining: remaining, Result: result})
+}
+
+func (s *Server) handleCollections(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	all := append([]api.Collection(nil), s.collections...)
+	s.Unlock()
+
+	switch parts := subPath(r, collections.ListCollectionsEndpoint); len(parts) {
+	case 0:
+	case 1:
+		for _, c := range all {
+			if strings.EqualFold(c.Address, parts[0]) {
+				writeJSON(w, http.StatusOK, c)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, fmt.Sprintf("collection %s not found", parts[0]))
+		return
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	q := r.URL.Query()
+	whitelist := splitList(q.Get("whitelist"))
+	blacklist := splitList(q.Get("blacklist"))
+
+	var matched []api.Collection
+	for _, c := range all {
+		addr := strings.ToLower(c.Address)
+		switch {
+		case len(whitelist) > 0 && !whitelist[addr],
+			blacklist[addr],
+			!contains(c.Name, q.Get("keyword")):
+			continue
+		}
+		matched = append(matched, c)
+	}
+
+	if !order(w, q, matched, collectionKeys, nil) {
+		return
+	}
+
+	result, cursor, remaining, ok := paginate(w, q, matched)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, api.ListCollectionsResponse{Cursor: cursor, Remaining: remaining, Result: result})
+}
+
+func (s *Server) handleOrders(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	all := append([]api.Order(nil), s.orders...)
+	byToken := make(map[string]api.AssetWithOrders, len(s.assets))
+	for _, a := range s.assets {
+		byToken[strings.ToLower(a.TokenAddress)+"/"+a.TokenId] = a
+	}
+	s.Unlock()
+
+	switch parts := subPath(r, orders.ListOrdersEndpoint); len(parts) {
+	case 0:
+	case 1:
+		for _, o := range all {
+			if fmt.Sprint(o.OrderId) == parts[0] {
+				writeJSON(w, http.StatusOK, o)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, fmt.Sprintf("order %s not found", parts[0]))
+		return
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	q := r.URL.Query()
+	metadata := make(map[string]map[string][]string)
+	for _, side := range []string{"buy", "sell"} {
+		if m := q.Get(side + "_metadata"); m != "" {
+			var parsed map[string][]string
+			if err := json.Unmarshal([]byte(m), &parsed); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid "+side+"_metadata: "+err.Error())
+				return
+			}
+			metadata[side] = parsed
+		}
+	}
+
+	var matched []api.Order
+	for _, o := range all {
+		if !matchFold(q.Get("status"), o.Status) ||
+			!matchFold(q.Get("user"), o.User) ||
+			!inRange(nullable(o.Timestamp), q.Get("min_timestamp"), q.Get("max_timestamp")) ||
+			!inRange(o.GetUpdatedTimestamp(), q.Get("updated_min_timestamp"), q.Get("updated_max_timestamp")) {
+			continue
+		}
+
+		if matchSide(q, "buy", o.Buy.Type, o.Buy.Data.GetTokenAddress(), o.Buy.Data.GetTokenId(), o.Buy.Data.GetId(), o.Buy.Data.Quantity, byToken, metadata["buy"]) &&
+			matchSide(q, "sell", o.Sell.Type, o.Sell.Data.GetTokenAddress(), o.Sell.Data.GetTokenId(), o.Sell.Data.GetId(), o.Sell.Data.Quantity, byToken, metadata["sell"]) {
+			matched = append(matched, o)
+		}
+	}
+
+	if !order(w, q, matched, orderKeys, orderNumericKeys) {
+		return
+	}
+
+	result, cursor, remaining, ok := paginate(w, q, matched)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, api.ListOrdersResponse{Cursor: cursor, Remaining: remaining, Result: result})
+}
+
+// matchSide applies the buy_ or sell_ prefixed filters to one side of an
+// order. Name and metadata filters look the token up in the seeded assets.
+func matchSide(q url.Values, side, tokenType, tokenAddress, tokenID, assetID, qty string, byToken map[string]api.AssetWithOrders, metadata map[string][]string) bool {
+	if !matchFold(q.Get(side+"_token_address"), tokenAddress) ||
+		!matchFold(q.Get(side+"_token_id"), tokenID) ||
+		!matchFold(q.Get(side+"_asset_id"), assetID) ||
+		!matchFold(q.Get(side+"_token_type"), tokenType) ||
+		!quantityInRange(qty, q.Get(side+"_min_quantity"), q.Get(side+"_max_quantity")) {
+		return false
+	}
+
+	name := q.Get(side + "_token_name")
+	if name == "" && metadata == nil {
+		return true
+	}
+
+	a, ok := byToken[strings.ToLower(tokenAddress)+"/"+tokenID]
+	return ok && contains(a.GetName(), name) && matchMetadata(metadata, a.Metadata)
+}
+
+// The order_by values supported for each list endpoint.
+var (
+	assetKeys = map[string]func(a api.AssetWithOrders) string{
+		"updated_at": func(a api.AssetWithOrders) string { return a.GetUpdatedAt() },
+		"name":       func(a api.AssetWithOrders) string { return a.GetName() },
+	}
+	collectionKeys = map[string]func(c api.Collection) string{
+		"name":       func(c api.Collection) string { return c.Name },
+		"address":    func(c api.Collection) string { return strings.ToLower(c.Address) },
+		"updated_at": func(c api.Collection) string { return c.GetUpdatedAt() },
+		"created_at": func(c api.Collection) string { return nullable(c.CreatedAt) },
+	}
+	orderKeys = map[string]func(o api.Order) string{
+		"created_at": func(o api.Order) string { return nullable(o.Timestamp) },
+		"updated_at": func(o api.Order) string { return o.GetUpdatedTimestamp() },
+	}
+	orderNumericKeys = map[string]func(o api.Order) *big.Int{
+		"order_id":      func(o api.Order) *big.Int { return big.NewInt(int64(o.OrderId)) },
+		"buy_quantity":  func(o api.Order) *big.Int { return quantity(o.Buy.Data.Quantity) },
+		"sell_quantity": func(o api.Order) *big.Int { return quantity(o.Sell.Data.Quantity) },
+	}
+)
+
+// order sorts items by the order_by and direction parameters, see sortItems.
+func order[T any](w http.ResponseWriter, q url.Values, items []T, keys map[string]func(T) string, numeric map[string]func(T) *big.Int) bool {
+	if err := sortItems(items, q.Get("order_by"), q.Get("direction"), keys, numeric); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	return true
+}
+
+// sortItems sorts items by one of the keys. Without a key the fixture order
+// is kept. Direction defaults to desc like the real API.
+func sortItems[T any](items []T, by, direction string, keys map[string]func(T) string, numeric map[string]func(T) *big.Int) error {
+	if by == "" {
+		return nil
+	}
+
+	var less func(i, j int) bool
+	if key, ok := keys[by]; ok {
+		less = func(i, j int) bool { return key(items[i]) < key(items[j]) }
+	} else if key, ok := numeric[by]; ok {
+		less = func(i, j int) bool { return key(items[i]).Cmp(key(items[j])) < 0 }
+	} else {
+		return fmt.Errorf("unsupported order_by: %s", by)
+	}
+
+	switch direction {
+	case "asc":
+	case "", "desc":
+		asc := less
+		less = func(i, j int) bool { return asc(j, i) }
+	default:
+		return fmt.Errorf("invalid direction: %s", direction)
+	}
+
+	sort.SliceStable(items, less)
+	return nil
+}
+
+// paginate applies the cursor and page_size parameters. Cursors are opaque
+// to clients but are just an encoded offset.
+func paginate[T any](w http.ResponseWriter, q url.Values, items []T) ([]T, string, int32, bool) {
+	size := DefaultPageSize
+	if p := q.Get("page_size"); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid page_size: "+p)
+			return nil, "", 0, false
+		}
+		if n > MaxPageSize {
+			n = MaxPageSize
+		}
+		size = n
+	}
+
+	offset := 0
+	if c := q.Get("cursor"); c != "" {
+		n, err := decodeCursor(c)
+		if err != nil || n > len(items) {
+			writeError(w, http.StatusBadRequest, "invalid cursor")
+			return nil, "", 0, false
+		}
+		offset = n
+	}
+
+	end := offset + size
+	if end > len(items) {
+		end = len(items)
+	}
+
+	result := items[offset:end]
+	if result == nil {
+		result = []T{}
+	}
+
+	var remaining int32
+	if end < len(items) {
+		remaining = 1
+	}
+
+	return result, encodeCursor(end), remaining, true
+}
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"offset":%d}`, offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	var c struct {
+		Offset int `json:"offset"`
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return 0, err
+	}
+
+	if c.Offset < 0 {
+		return 0, fmt.Errorf("negative offset")
+	}
+
+	return c.Offset, nil
+}
+
+// asset converts a listed asset to the single asset response.
+func asset(a api.AssetWithOrders) api.Asset {
+	var result api.Asset
+	b, _ := json.Marshal(a)
+	json.Unmarshal(b, &result)
+	return result
+}
+
+func nullable(v api.NullableString) string {
+	if p := v.Get(); p != nil {
+		return *p
+	}
+
+	return ""
+}
+
+func matchFold(want, got string) bool {
+	return want == "" || strings.EqualFold(want, got)
+}
+
+func contains(s, substr string) bool {
+	return substr == "" || strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func splitList(s string) map[string]bool {
+	m := make(map[string]bool)
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			m[strings.ToLower(v)] = true
+		}
+	}
+
+	return m
+}
+
+// inRange compares timestamps as strings, which works for the API's
+// RFC 3339 values.
+func inRange(ts, min, max string) bool {
+	if min != "" && ts < min {
+		return false
+	}
+
+	if max != "" && ts > max {
+		return false
+	}
+
+	return true
+}
+
+func quantity(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return new(big.Int)
+	}
+
+	return n
+}
+
+func quantityInRange(q, min, max string) bool {
+	n := quantity(q)
+	if min != "" && n.Cmp(quantity(min)) < 0 {
+		return false
+	}
+
+	if max != "" && n.Cmp(quantity(max)) > 0 {
+		return false
+	}
+
+	return true
+}
+
+// matchMetadata reports whether every filtered property has one of the
+// wanted values, e.g. {"Rarity": ["Epic", "Legendary"]}.
+func matchMetadata(filter map[string][]string, metadata map[string]interface{}) bool {
+	for key, values := range filter {
+		got, ok := metadata[key]
+		if !ok {
+			return false
+		}
+
+		found := false
+		for _, v := range values {
+			if fmt.Sprint(got) == v {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
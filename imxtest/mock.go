@@ -0,0 +1,98 @@
+package imxtest
+
+import (
+	"sync"
+)
+
+// Call is a method call received by a fake client.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Mock records calls and returns programmed errors. It's embedded in every
+// fake client.
+type Mock struct {
+	calls  []Call
+	errs   map[string]error
+	queued map[string][]error
+
+	sync.Mutex
+}
+
+// Calls returns every call so far, oldest first.
+func (m *Mock) Calls() []Call {
+	m.Lock()
+	defer m.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns the calls to one method, e.g. "ListAssets".
+func (m *Mock) CallsTo(method string) []Call {
+	m.Lock()
+	defer m.Unlock()
+
+	var calls []Call
+	for _, c := range m.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+
+	return calls
+}
+
+// SetError makes every call to method fail with err, nil clears it.
+func (m *Mock) SetError(method string, err error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.errs == nil {
+		m.errs = make(map[string]error)
+	}
+
+	if err == nil {
+		delete(m.errs, method)
+		return
+	}
+
+	m.errs[method] = err
+}
+
+// FailNext makes the next call to method fail with err. Queued errors are
+// returned in order before any set with SetError.
+func (m *Mock) FailNext(method string, err error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.queued == nil {
+		m.queued = make(map[string][]error)
+	}
+
+	m.queued[method] = append(m.queued[method], err)
+}
+
+// ResetCalls forgets recorded calls and programmed errors.
+func (m *Mock) ResetCalls() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.calls = nil
+	m.errs = nil
+	m.queued = nil
+}
+
+// record notes a call and returns the error it should fail with, if any.
+func (m *Mock) record(method string, args ...interface{}) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+
+	if q := m.queued[method]; len(q) > 0 {
+		m.queued[method] = q[1:]
+		return q[0]
+	}
+
+	return m.errs[method]
+}
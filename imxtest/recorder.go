@@ -0,0 +1,278 @@
+package imxtest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RecordEnv switches NewRecorderFromEnv into record mode when set to "1",
+// e.g. IMX_RECORD=1 go test ./...
+const RecordEnv = "IMX_RECORD"
+
+const redacted = "REDACTED"
+
+type Mode int
+
+const (
+	// ModeReplay serves responses from golden files and fails requests
+	// without one, so tests never reach the network.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends requests to the network and writes the responses to
+	// golden files, replacing existing ones.
+	ModeRecord
+)
+
+var ErrNoRecording = errors.New("no recording for request")
+
+// scrubHeaders are never written to golden files.
+var scrubHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// Interaction is a golden file: one request and the response it received.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int             `json:"status_code"`
+	Header     http.Header     `json:"header,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+
+	// BodyText holds bodies that aren't JSON.
+	BodyText string `json:"body_text,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records responses to golden files
+// in Dir or replays them. Secrets, such as the Alchemy key, are replaced in
+// URLs, headers and bodies before anything is written, and requests are
+// matched on their scrubbed form so replays work with any key.
+//
+//	rec := imxtest.NewRecorderFromEnv("testdata/golden", os.Getenv("IMX_ALCHEMY_KEY"))
+//	client := assets.NewRESTClient(assets.RESTClientConfig{URL: utils.DefaultImmutableAPIURL, HTTPClient: rec.Client()})
+//	prices := coinbase.NewCoinbaseClient(coinbase.CoinbaseClientConfig{HTTPClient: rec.Client()})
+//	renderer := notify.Renderer{Fiat: coinbase.FiatUSD, Prices: prices}
+type Recorder struct {
+	Dir     string
+	Mode    Mode
+	Secrets []string
+
+	// Transport sends requests in record mode, http.DefaultTransport when nil.
+	Transport http.RoundTripper
+
+	sync.Mutex
+}
+
+func NewRecorder(dir string, mode Mode, secrets ...string) *Recorder {
+	return &Recorder{Dir: dir, Mode: mode, Secrets: secrets}
+}
+
+// NewRecorderFromEnv replays unless RecordEnv is "1".
+func NewRecorderFromEnv(dir string, secrets ...string) *Recorder {
+	mode := ModeReplay
+	if os.Getenv(RecordEnv) == "1" {
+		mode = ModeRecord
+	}
+
+	return NewRecorder(dir, mode, secrets...)
+}
+
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+		req.Body = io.NopCloser(bytes.NewReader(b))
+	}
+
+	recorded := RecordedRequest{
+		Method: req.Method,
+		URL:    r.scrub(req.URL.String()),
+		Body:   rawJSON(r.scrub(string(body))),
+	}
+	path := filepath.Join(r.Dir, r.filename(recorded, body))
+
+	if r.Mode == ModeReplay {
+		return r.replay(req, recorded, path)
+	}
+
+	return r.record(req, recorded, path)
+}
+
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest, path string) (*http.Response, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s (%s), set %s=1 to record it", ErrNoRecording, recorded.Method, recorded.URL, path, RecordEnv)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var in Interaction
+	if err := json.Unmarshal(b, &in); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", path, err)
+	}
+
+	respBody := []byte(in.Response.BodyText)
+	if len(in.Response.Body) > 0 {
+		respBody = in.Response.Body
+	}
+
+	header := in.Response.Header
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+		StatusCode:    in.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest, path string) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := resp.Header.Clone()
+	for _, h := range scrubHeaders {
+		if header.Get(h) != "" {
+			header.Set(h, redacted)
+		}
+	}
+	for key, values := range header {
+		for i, v := range values {
+			values[i] = r.scrub(v)
+		}
+		header[key] = values
+	}
+	// Bodies are stored decoded and the length may change after scrubbing.
+	header.Del("Content-Encoding")
+	header.Del("Content-Length")
+
+	in := Interaction{
+		Request:  recorded,
+		Response: RecordedResponse{StatusCode: resp.StatusCode, Header: header},
+	}
+
+	scrubbed := r.scrub(string(body))
+	if raw := rawJSON(scrubbed); raw != nil {
+		in.Response.Body = raw
+	} else {
+		in.Response.BodyText = scrubbed
+	}
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(in); err != nil {
+		return nil, err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *Recorder) scrub(s string) string {
+	for _, secret := range r.Secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+
+	return s
+}
+
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// filename is readable for browsing golden files but unique per scrubbed
+// request, with query parameters sorted so their order doesn't matter.
+func (r *Recorder) filename(recorded RecordedRequest, body []byte) string {
+	host, path := "", recorded.URL
+	if i := strings.Index(path, "://"); i >= 0 {
+		path = path[i+3:]
+	}
+	if i := strings.Index(path, "/"); i >= 0 {
+		host, path = path[:i], path[i:]
+	}
+
+	query := ""
+	if i := strings.Index(path, "?"); i >= 0 {
+		params := strings.Split(path[i+1:], "&")
+		sort.Strings(params)
+		path, query = path[:i], strings.Join(params, "&")
+	}
+
+	sum := sha256.Sum256([]byte(recorded.Method + " " + host + path + "?" + query + "\n" + r.scrub(string(body))))
+	name := strings.Trim(unsafeChars.ReplaceAllString(path, "_"), "_")
+	if len(name) > 80 {
+		name = name[:80]
+	}
+
+	return filepath.Join(unsafeChars.ReplaceAllString(host, "_"), fmt.Sprintf("%s_%s_%s.json", strings.ToLower(recorded.Method), name, hex.EncodeToString(sum[:])[:12]))
+}
+
+func rawJSON(s string) json.RawMessage {
+	if s == "" || !json.Valid([]byte(s)) {
+		return nil
+	}
+
+	return json.RawMessage(s)
+}
@@ -0,0 +1,175 @@
+package imxtest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/deadloct/immutablex-go-lib/imxtest"
+)
+
+const secret = "s3cret-key"
+
+// upstream echoes the request and the secret back in the response headers and
+// body.
+func upstream(t *testing.T, hits *int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.Header().Set("X-Echo", "key="+secret)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"path": "` + r.URL.Path + `", "request": ` + string(body) + `}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func send(t *testing.T, client *http.Client, url string) (*http.Response, string, error) {
+	t.Helper()
+
+	resp, err := client.Post(url, "application/json", strings.NewReader(`{"apiKey": "`+secret+`"}`))
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp, string(body), nil
+}
+
+func compact(t *testing.T, s string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(s)); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func goldenFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return files
+}
+
+func TestRecorderScrubsSecrets(t *testing.T) {
+	var hits int32
+	srv := upstream(t, &hits)
+	dir := t.TempDir()
+	rec := imxtest.NewRecorder(dir, imxtest.ModeRecord, secret)
+
+	resp, body, err := send(t, rec.Client(), srv.URL+"/v2/"+secret+"/getNFTs?owner=0x1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The caller gets the real response.
+	if !strings.Contains(body, secret) || resp.Header.Get("X-Echo") != "key="+secret {
+		t.Errorf("got response %s with headers %v, want it unscrubbed", body, resp.Header)
+	}
+
+	files := goldenFiles(t, dir)
+	if len(files) != 1 {
+		t.Fatalf("got golden files %v, want 1", files)
+	}
+
+	if strings.Contains(files[0], secret) {
+		t.Errorf("golden file name %s contains the secret", files[0])
+	}
+
+	golden, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(golden), secret) || strings.Contains(string(golden), "session=abc") {
+		t.Errorf("golden file contains a secret:\n%s", golden)
+	}
+
+	for _, want := range []string{`"url": "` + srv.URL + `/v2/REDACTED/getNFTs?owner=0x1"`, `"apiKey": "REDACTED"`, `"key=REDACTED"`} {
+		if !strings.Contains(string(golden), want) {
+			t.Errorf("golden file doesn't contain %s:\n%s", want, golden)
+		}
+	}
+}
+
+func TestRecorderRoundTrip(t *testing.T) {
+	var hits int32
+	srv := upstream(t, &hits)
+	dir := t.TempDir()
+
+	_, recorded, err := send(t, imxtest.NewRecorder(dir, imxtest.ModeRecord, secret).Client(), srv.URL+"/v1/assets?b=2&a=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Replays match on the scrubbed request, so query parameters can be in any
+	// order.
+	replay := imxtest.NewRecorder(dir, imxtest.ModeReplay, secret).Client()
+	for _, query := range []string{"b=2&a=1", "a=1&b=2"} {
+		resp, body, err := send(t, replay, srv.URL+"/v1/assets?"+query)
+		if err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("%s: got status %d and headers %v", query, resp.StatusCode, resp.Header)
+		}
+
+		// JSON bodies are stored indented.
+		if got, want := compact(t, body), compact(t, strings.ReplaceAll(recorded, secret, "REDACTED")); got != want {
+			t.Errorf("%s: got body %s, want %s", query, got, want)
+		}
+	}
+
+	if hits := atomic.LoadInt32(&hits); hits != 1 {
+		t.Errorf("upstream got %d requests, want 1", hits)
+	}
+
+	if files := goldenFiles(t, dir); len(files) != 1 {
+		t.Errorf("got golden files %v, want 1", files)
+	}
+}
+
+func TestRecorderReplayWithoutRecording(t *testing.T) {
+	var hits int32
+	srv := upstream(t, &hits)
+
+	_, _, err := send(t, imxtest.NewRecorder(t.TempDir(), imxtest.ModeReplay, secret).Client(), srv.URL+"/v1/assets")
+	if !errors.Is(err, imxtest.ErrNoRecording) {
+		t.Errorf("got error %v, want ErrNoRecording", err)
+	}
+
+	if hits := atomic.LoadInt32(&hits); hits != 0 {
+		t.Errorf("upstream got %d requests in replay mode, want 0", hits)
+	}
+}
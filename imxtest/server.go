@@ -0,0 +1,209 @@
+// Package imxtest provides an in-process fake of the Immutable X REST API
+// for tests, e.g.
+//
+//	srv := imxtest.NewServer(imxtest.DefaultFixtures())
+//	defer srv.Close()
+//
+//	client := assets.NewRESTClient(srv.AssetsConfig())
+//	srv.InjectFault(imxtest.Fault{Path: assets.ListAssetsEndpoint, Status: http.StatusTooManyRequests, Times: 1})
+package imxtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 200
+)
+
+// Fault changes how matching requests are answered. Latency is applied
+// first, then Status or Malformed if set.
+type Fault struct {
+	// Path prefix to match, e.g. "/v3/orders". Empty matches everything.
+	Path string
+
+	Latency time.Duration
+
+	// Status responds with an API error body, e.g. 429 or 503. 429s include
+	// a Retry-After header.
+	Status int
+
+	// Malformed responds 200 with a truncated JSON body.
+	Malformed bool
+
+	// Times limits how many requests the fault applies to, 0 is forever.
+	Times int
+}
+
+// Request is a request the server has received.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+}
+
+type Server struct {
+	*httptest.Server
+
+	assets      []api.AssetWithOrders
+	collections []api.Collection
+	orders      []api.Order
+	faults      []*Fault
+	requests    []Request
+
+	sync.Mutex
+}
+
+// NewServer starts a fake seeded with f, which may be nil.
+func NewServer(f *Fixtures) *Server {
+	s := &Server{}
+	if f != nil {
+		s.Seed(f)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(assets.ListAssetsEndpoint, s.handleAssets)
+	mux.HandleFunc(assets.ListAssetsEndpoint+"/", s.handleAssets)
+	mux.HandleFunc(collections.ListCollectionsEndpoint, s.handleCollections)
+	mux.HandleFunc(collections.ListCollectionsEndpoint+"/", s.handleCollections)
+	mux.HandleFunc(orders.ListOrdersEndpoint, s.handleOrders)
+	mux.HandleFunc(orders.ListOrdersEndpoint+"/", s.handleOrders)
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+	return s
+}
+
+func (s *Server) AssetsConfig() assets.RESTClientConfig {
+	return assets.RESTClientConfig{URL: s.URL}
+}
+
+func (s *Server) CollectionsConfig() collections.RESTClientConfig {
+	return collections.RESTClientConfig{URL: s.URL}
+}
+
+func (s *Server) OrdersConfig() orders.RESTClientConfig {
+	return orders.RESTClientConfig{URL: s.URL}
+}
+
+// Seed adds fixtures to the data already being served.
+func (s *Server) Seed(f *Fixtures) {
+	s.AddCollections(f.Collections...)
+	s.AddAssets(f.Assets...)
+	s.AddOrders(f.Orders...)
+}
+
+func (s *Server) AddAssets(a ...api.AssetWithOrders) {
+	s.Lock()
+	defer s.Unlock()
+	s.assets = append(s.assets, a...)
+}
+
+func (s *Server) AddCollections(c ...api.Collection) {
+	s.Lock()
+	defer s.Unlock()
+	s.collections = append(s.collections, c...)
+}
+
+func (s *Server) AddOrders(o ...api.Order) {
+	s.Lock()
+	defer s.Unlock()
+	s.orders = append(s.orders, o...)
+}
+
+// Reset drops all data, faults and recorded requests.
+func (s *Server) Reset() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.assets = nil
+	s.collections = nil
+	s.orders = nil
+	s.faults = nil
+	s.requests = nil
+}
+
+func (s *Server) InjectFault(f Fault) {
+	s.Lock()
+	defer s.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+func (s *Server) ClearFaults() {
+	s.Lock()
+	defer s.Unlock()
+	s.faults = nil
+}
+
+// Requests returns every request received so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.Lock()
+	defer s.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Lock()
+		s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query()})
+		fault := s.takeFault(r.URL.Path)
+		s.Unlock()
+
+		if fault == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		switch {
+		case fault.Status != 0:
+			if fault.Status == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "1")
+			}
+			writeError(w, fault.Status, http.StatusText(fault.Status))
+		case fault.Malformed:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"result": [{"token_address": `))
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// takeFault returns the first fault matching path, counting it against
+// Times. Must be called with the lock held.
+func (s *Server) takeFault(path string) *Fault {
+	for i, f := range s.faults {
+		if !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+
+		return f
+	}
+
+	return nil
+}
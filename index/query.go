@@ -0,0 +1,168 @@
+package index
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Query filters indexed assets. Empty fields match everything.
+type Query struct {
+	Owner  string
+	Status string
+	// Name matches case-insensitive substrings of the asset name.
+	Name string
+	// Metadata matches assets whose metadata values equal the given ones when
+	// formatted as strings.
+	Metadata      map[string]string
+	IncludeBurned bool
+	Limit         int
+}
+
+func (q Query) matches(rec *Record) bool {
+	a := rec.Asset
+	switch {
+	case rec.Burned && !q.IncludeBurned:
+		return false
+	case q.Owner != "" && !strings.EqualFold(a.User, q.Owner):
+		return false
+	case q.Status != "" && a.Status != q.Status:
+		return false
+	case q.Name != "" && !strings.Contains(strings.ToLower(a.GetName()), strings.ToLower(q.Name)):
+		return false
+	}
+
+	for k, v := range q.Metadata {
+		if fmt.Sprint(a.Metadata[k]) != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetAsset returns an indexed asset, including tombstones, or ErrNotFound.
+func (s *Store) GetAsset(collection, tokenID string) (*Record, error) {
+	var rec *Record
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := collectionBucket(tx, collection)
+		if b == nil {
+			return ErrNotFound
+		}
+
+		var err error
+		rec, err = getRecord(b, tokenID)
+		return err
+	})
+
+	return rec, err
+}
+
+// QueryAssets returns the indexed assets of a collection matching q, in
+// numeric token ID order.
+func (s *Store) QueryAssets(collection string, q Query) ([]Record, error) {
+	var records []Record
+	err := s.forEach(collection, func(rec *Record) bool {
+		if q.matches(rec) {
+			records = append(records, *rec)
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys are the token IDs as stored, which sort "10" before "9", so the
+	// limit can only be applied once every match is sorted.
+	sort.Slice(records, func(i, j int) bool {
+		return lessTokenID(records[i].Asset.TokenId, records[j].Asset.TokenId)
+	})
+
+	if q.Limit > 0 && len(records) > q.Limit {
+		records = records[:q.Limit]
+	}
+
+	return records, nil
+}
+
+// lessTokenID orders decimal token IDs numerically, without parsing them as
+// they can be up to 256 bits, and anything else after them as strings.
+func lessTokenID(a, b string) bool {
+	an, bn := isDecimal(a), isDecimal(b)
+	switch {
+	case an && bn:
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	case an != bn:
+		return an
+	default:
+		return a < b
+	}
+}
+
+func isDecimal(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Owners counts the unburned assets of a collection held by each owner.
+func (s *Store) Owners(collection string) (map[string]int, error) {
+	owners := make(map[string]int)
+	err := s.forEach(collection, func(rec *Record) bool {
+		if !rec.Burned {
+			owners[strings.ToLower(rec.Asset.User)]++
+		}
+
+		return true
+	})
+
+	return owners, err
+}
+
+func (s *Store) forEach(collection string, fn func(rec *Record) bool) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		b := collectionBucket(tx, collection)
+		if b == nil {
+			return nil
+		}
+
+		c := b.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var rec Record
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return fmt.Errorf("corrupt index entry %s/%s: %w", collection, k, err)
+			}
+
+			if !fn(&rec) {
+				return nil
+			}
+		}
+
+		return nil
+	})
+}
+
+func collectionBucket(tx *bolt.Tx, collection string) *bolt.Bucket {
+	root := tx.Bucket(assetsBucket)
+	if root == nil {
+		return nil
+	}
+
+	return root.Bucket(collectionKey(collection))
+}
@@ -0,0 +1,82 @@
+package index
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+func TestQueryAssetsTokenIDOrder(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "index.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var page []api.AssetWithOrders
+	for _, id := range []string{"10", "9", "100", "2", "1"} {
+		var a api.AssetWithOrders
+		if err := json.Unmarshal([]byte(`{"token_address": "0xabc", "token_id": "`+id+`", "status": "imx"}`), &a); err != nil {
+			t.Fatal(err)
+		}
+		page = append(page, a)
+	}
+
+	state := &SyncState{Collection: "0xabc"}
+	if _, err := s.apply(state, page, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[int][]string{
+		0: {"1", "2", "9", "10", "100"},
+		3: {"1", "2", "9"},
+	}
+
+	for limit, want := range tests {
+		records, err := s.QueryAssets("0xabc", Query{Limit: limit})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, r := range records {
+			got = append(got, r.Asset.TokenId)
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("limit %d: got %v, want %v", limit, got, want)
+		}
+
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("limit %d: got %v, want %v", limit, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestLessTokenID(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"9", "10", true},
+		{"10", "9", false},
+		{"007", "10", true},
+		{"115792089237316195423570985008687907853269984665640564039457584007913129639935", "2", false},
+		{"999", "abc", true},
+		{"abc", "999", false},
+		{"abc", "abd", true},
+		{"5", "5", false},
+	}
+
+	for _, tt := range tests {
+		if got := lessTokenID(tt.a, tt.b); got != tt.want {
+			t.Errorf("lessTokenID(%q, %q) = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Package index keeps a local copy of collections' assets in a bbolt database
+// and keeps it current by fetching only the assets updated since the last sync.
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/utils"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+	bolt "go.etcd.io/bbolt"
+)
+
+// StatusBurned is the asset status the API reports once a token is burned.
+const StatusBurned = "burned"
+
+var (
+	ErrNotFound = errors.New("not found in index")
+
+	stateBucket  = []byte("state")
+	assetsBucket = []byte("assets")
+)
+
+// Record is an indexed asset. Burned assets are kept as tombstones so a later
+// sync can't resurrect them from stale pages, and are hidden from queries by
+// default. A tombstone is only replaced by an asset updated after it.
+type Record struct {
+	Asset    api.AssetWithOrders `json:"asset"`
+	Burned   bool                `json:"burned"`
+	BurnedAt *time.Time          `json:"burned_at,omitempty"`
+	SyncedAt time.Time           `json:"synced_at"`
+}
+
+// SyncState tracks how far a collection has been synced.
+type SyncState struct {
+	Collection string `json:"collection"`
+	utils.SyncCheckpoint
+	// Assets counts indexed assets, including tombstones.
+	Assets int `json:"assets"`
+}
+
+type Store struct {
+	db *bolt.DB
+}
+
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("could not open index %s: %w", path, err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// collectionKey resolves shortcuts, so "hero" and its address share an index.
+func collectionKey(collection string) []byte {
+	return []byte(strings.ToLower(collections.ResolveCollection("collection", collection)))
+}
+
+// State returns the sync state of a collection, which is empty if it has never
+// been synced.
+func (s *Store) State(collection string) (SyncState, error) {
+	state := SyncState{Collection: string(collectionKey(collection))}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(stateBucket)
+		if b == nil {
+			return nil
+		}
+
+		data := b.Get(collectionKey(collection))
+		if data == nil {
+			return nil
+		}
+
+		return json.Unmarshal(data, &state)
+	})
+
+	return state, err
+}
+
+// Collections returns the sync state of every indexed collection.
+func (s *Store) Collections() ([]SyncState, error) {
+	var states []SyncState
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(stateBucket)
+		if b == nil {
+			return nil
+		}
+
+		return b.ForEach(func(k, v []byte) error {
+			var state SyncState
+			if err := json.Unmarshal(v, &state); err != nil {
+				return err
+			}
+
+			states = append(states, state)
+			return nil
+		})
+	})
+
+	return states, err
+}
+
+// apply stores a page of assets and the state after it in one transaction, so
+// the cursor never gets ahead of the data.
+func (s *Store) apply(state *SyncState, page []api.AssetWithOrders, now time.Time) (burned int, err error) {
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists(assetsBucket)
+		if err != nil {
+			return err
+		}
+
+		b, err := root.CreateBucketIfNotExists(collectionKey(state.Collection))
+		if err != nil {
+			return err
+		}
+
+		for _, asset := range page {
+			prev, err := getRecord(b, asset.TokenId)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return err
+			}
+
+			if prev != nil && prev.Burned && asset.Status != StatusBurned && !utils.LaterTimestamp(asset.GetUpdatedAt(), prev.Asset.GetUpdatedAt()) {
+				// A stale page from before the burn.
+				continue
+			}
+
+			rec := Record{Asset: asset, SyncedAt: now}
+			if asset.Status == StatusBurned {
+				if prev != nil && prev.Burned {
+					rec.BurnedAt = prev.BurnedAt
+				} else {
+					rec.BurnedAt = &now
+				}
+				rec.Burned = true
+				burned++
+			}
+
+			data, err := json.Marshal(rec)
+			if err != nil {
+				return err
+			}
+
+			if prev == nil {
+				state.Assets++
+			}
+
+			if err := b.Put([]byte(asset.TokenId), data); err != nil {
+				return err
+			}
+
+			if updated := asset.UpdatedAt.Get(); updated != nil && utils.LaterTimestamp(*updated, state.UpdatedAt) {
+				state.UpdatedAt = *updated
+			}
+		}
+
+		return putState(tx, state)
+	})
+
+	return burned, err
+}
+
+func putState(tx *bolt.Tx, state *SyncState) error {
+	b, err := tx.CreateBucketIfNotExists(stateBucket)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(collectionKey(state.Collection), data)
+}
+
+func getRecord(b *bolt.Bucket, tokenID string) (*Record, error) {
+	data := b.Get([]byte(tokenID))
+	if data == nil {
+		return nil, ErrNotFound
+	}
+
+	var rec Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, err
+	}
+
+	return &rec, nil
+}
@@ -0,0 +1,81 @@
+package index
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+func openStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := Open(filepath.Join(t.TempDir(), "index.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	return s
+}
+
+func assetAt(t *testing.T, id, status, updated string) api.AssetWithOrders {
+	t.Helper()
+
+	var a api.AssetWithOrders
+	data := `{"token_address": "0xabc", "token_id": "` + id + `", "status": "` + status + `", "updated_at": "` + updated + `"}`
+	if err := json.Unmarshal([]byte(data), &a); err != nil {
+		t.Fatal(err)
+	}
+
+	return a
+}
+
+func TestApplyKeepsTombstones(t *testing.T) {
+	s := openStore(t)
+	state := &SyncState{Collection: "0xabc"}
+	burnedAt := time.Date(2022, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	pages := []struct {
+		asset      api.AssetWithOrders
+		wantStatus string
+		wantBurned bool
+	}{
+		{assetAt(t, "1", "imx", "2022-03-01T00:00:00Z"), "imx", false},
+		{assetAt(t, "1", StatusBurned, "2022-03-02T00:00:00Z"), StatusBurned, true},
+		// Stale pages from before the burn, or from the same instant, don't
+		// bring the asset back.
+		{assetAt(t, "1", "imx", "2022-03-01T00:00:00Z"), StatusBurned, true},
+		{assetAt(t, "1", "imx", "2022-03-02T00:00:00Z"), StatusBurned, true},
+		// A repeated burn keeps the original burn time.
+		{assetAt(t, "1", StatusBurned, "2022-03-02T00:00:00Z"), StatusBurned, true},
+		// Only a strictly newer update replaces the tombstone.
+		{assetAt(t, "1", "imx", "2022-03-03T00:00:00Z"), "imx", false},
+	}
+
+	for i, p := range pages {
+		now := burnedAt.Add(time.Duration(i) * time.Hour)
+		if _, err := s.apply(state, []api.AssetWithOrders{p.asset}, now); err != nil {
+			t.Fatal(err)
+		}
+
+		rec, err := s.GetAsset("0xabc", "1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if rec.Asset.Status != p.wantStatus || rec.Burned != p.wantBurned {
+			t.Errorf("page %d: got status %s, burned %t, want %s, %t", i, rec.Asset.Status, rec.Burned, p.wantStatus, p.wantBurned)
+		}
+
+		if rec.Burned && !rec.BurnedAt.Equal(burnedAt.Add(time.Hour)) {
+			t.Errorf("page %d: got burned at %v, want the first burn", i, rec.BurnedAt)
+		}
+	}
+
+	if state.Assets != 1 {
+		t.Errorf("got %d assets, want 1", state.Assets)
+	}
+}
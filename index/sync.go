@@ -0,0 +1,84 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/utils"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+	log "github.com/sirupsen/logrus"
+)
+
+const DefaultSyncPageSize = 200
+
+type Syncer struct {
+	client   assets.Client
+	store    *Store
+	pageSize int
+}
+
+// NewSyncer syncs collections into store using client, fetching pageSize
+// assets per request, or DefaultSyncPageSize if pageSize isn't positive.
+func NewSyncer(client assets.Client, store *Store, pageSize int) *Syncer {
+	if pageSize <= 0 {
+		pageSize = DefaultSyncPageSize
+	}
+
+	return &Syncer{client: client, store: store, pageSize: pageSize}
+}
+
+type SyncResult struct {
+	Pages   int
+	Updated int
+	Burned  int
+}
+
+// Sync fetches the assets of a collection updated since its last sync, see
+// utils.SyncPages. The first sync of a collection downloads all of it.
+func (s *Syncer) Sync(ctx context.Context, collection string) (*SyncResult, error) {
+	state, err := s.store.State(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.Cursor != "" {
+		log.Debugf("resuming sync of %s from cursor %s", collection, state.Cursor)
+	}
+
+	cfg := assets.ListAssetsConfig{
+		Collection: state.Collection,
+		Direction:  "asc",
+		OrderBy:    "updated_at",
+		PageSize:   s.pageSize,
+	}
+
+	fetch := func(ctx context.Context, queryFrom, cursor string) ([]api.AssetWithOrders, string, error) {
+		cfg.Assets = nil
+		cfg.UpdatedMinTimestamp = queryFrom
+		cfg.Cursor = cursor
+		page, err := assets.ListAssetsPage(ctx, s.client, &cfg)
+		return page, cfg.Cursor, err
+	}
+
+	result := &SyncResult{}
+	store := func(page []api.AssetWithOrders, now time.Time) error {
+		burned, err := s.store.apply(&state, page, now)
+		if err != nil {
+			return err
+		}
+
+		result.Pages++
+		result.Updated += len(page)
+		result.Burned += burned
+		log.Debugf("synced page %d of %s: %d assets, watermark %s", result.Pages, collection, len(page), state.UpdatedAt)
+		return nil
+	}
+
+	if _, err := utils.SyncPages(ctx, &state.SyncCheckpoint, s.pageSize, fetch, store); err != nil {
+		return result, fmt.Errorf("sync of %s failed after %d pages: %w", collection, result.Pages, err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,145 @@
+// Package metrics is an optional hook for measuring the library's API
+// calls. Nothing is recorded until SetRecorder is called, e.g.
+//
+//	rec := metrics.NewPrometheusRecorder("imx")
+//	prometheus.MustRegister(rec)
+//	metrics.SetRecorder(rec)
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+const (
+	EndpointGetAsset        = "assets/get"
+	EndpointListAssets      = "assets/list"
+	EndpointGetCollection   = "collections/get"
+	EndpointListCollections = "collections/list"
+	EndpointGetOrder        = "orders/get"
+	EndpointListOrders      = "orders/list"
+	EndpointNotify          = "notify"
+	EndpointProxy           = "proxy"
+
+	BackendREST    = "rest"
+	BackendAlchemy = "alchemy"
+)
+
+// Recorder receives measurements from the clients. Implementations must be
+// safe for concurrent use and shouldn't block.
+type Recorder interface {
+	// Request is called once per request, after its body has been read.
+	// status is 0 when no response was received.
+	Request(endpoint, backend string, status int, duration time.Duration)
+	Retry(endpoint string)
+	// Page is called for every page of results fetched.
+	Page(endpoint string, items int)
+	CacheHit(endpoint string)
+	CacheMiss(endpoint string)
+	CoinbaseFetch(pair string, duration time.Duration, err error)
+}
+
+// NoopRecorder discards everything, it's the default.
+type NoopRecorder struct{}
+
+func (NoopRecorder) Request(endpoint, backend string, status int, duration time.Duration) {}
+func (NoopRecorder) Retry(endpoint string)                                                {}
+func (NoopRecorder) Page(endpoint string, items int)                                      {}
+func (NoopRecorder) CacheHit(endpoint string)                                             {}
+func (NoopRecorder) CacheMiss(endpoint string)                                            {}
+func (NoopRecorder) CoinbaseFetch(pair string, duration time.Duration, err error)         {}
+
+// EndpointOther is recorded for requests whose endpoint isn't known.
+const EndpointOther = "other"
+
+type endpointKey struct{}
+
+// WithEndpoint tags ctx with the endpoint its requests are recorded under, for
+// measurements taken where only the request is known, such as retries.
+func WithEndpoint(ctx context.Context, endpoint string) context.Context {
+	return context.WithValue(ctx, endpointKey{}, endpoint)
+}
+
+// EndpointFrom returns the endpoint ctx was tagged with, or EndpointOther.
+func EndpointFrom(ctx context.Context) string {
+	if endpoint, ok := ctx.Value(endpointKey{}).(string); ok {
+		return endpoint
+	}
+
+	return EndpointOther
+}
+
+var (
+	recorder Recorder = NoopRecorder{}
+	mu       sync.RWMutex
+)
+
+func GetRecorder() Recorder {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return recorder
+}
+
+// SetRecorder sends measurements to r, nil restores the NoopRecorder.
+func SetRecorder(r Recorder) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if r == nil {
+		r = NoopRecorder{}
+	}
+
+	recorder = r
+}
+
+// ObserveResponse records a REST request started at start.
+func ObserveResponse(endpoint string, start time.Time, resp *http.Response) {
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+
+	GetRecorder().Request(endpoint, BackendREST, status, time.Since(start))
+}
+
+// ObserveCall records an SDK call started at start.
+func ObserveCall(endpoint string, start time.Time, err error) {
+	GetRecorder().Request(endpoint, BackendAlchemy, callStatus(err), time.Since(start))
+}
+
+// callStatus returns the status of an SDK call. Errors for non 2xx responses
+// are a GenericOpenAPIError whose message is the status line, e.g. "404 Not
+// Found". Other errors mean no response was received.
+func callStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	var msg string
+	var apiErr *api.GenericOpenAPIError
+	var apiErrValue api.GenericOpenAPIError
+	switch {
+	case errors.As(err, &apiErr):
+		msg = apiErr.Error()
+	case errors.As(err, &apiErrValue):
+		msg = apiErrValue.Error()
+	default:
+		return 0
+	}
+
+	code, _, _ := strings.Cut(msg, " ")
+	status, err := strconv.Atoi(code)
+	if err != nil {
+		return 0
+	}
+
+	return status
+}
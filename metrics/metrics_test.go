@@ -0,0 +1,75 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/imxtest"
+	"github.com/deadloct/immutablex-go-lib/metrics"
+)
+
+type statusRecorder struct {
+	metrics.NoopRecorder
+
+	statuses []int
+	sync.Mutex
+}
+
+func (r *statusRecorder) Request(endpoint, backend string, status int, duration time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+	r.statuses = append(r.statuses, status)
+}
+
+func TestObserveCallStatus(t *testing.T) {
+	rec := &statusRecorder{}
+	metrics.SetRecorder(rec)
+	defer metrics.SetRecorder(nil)
+
+	srv := imxtest.NewServer(imxtest.DefaultFixtures())
+	defer srv.Close()
+
+	sdk, err := imxtest.NewSDKClient(srv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	_, err = sdk.Client.GetCollection(ctx, "0x0000000000000000000000000000000000000000")
+	if err == nil {
+		t.Fatal("expected an error for a missing collection")
+	}
+
+	start := time.Now()
+	metrics.ObserveCall(metrics.EndpointGetCollection, start, err)
+	metrics.ObserveCall(metrics.EndpointGetCollection, start, fmt.Errorf("wrapped: %w", err))
+	metrics.ObserveCall(metrics.EndpointGetCollection, start, context.DeadlineExceeded)
+	metrics.ObserveCall(metrics.EndpointGetCollection, start, errors.New("404 looks like a status"))
+	metrics.ObserveCall(metrics.EndpointGetCollection, start, nil)
+
+	want := []int{404, 404, 0, 0, 200}
+	if len(rec.statuses) != len(want) {
+		t.Fatalf("recorded %v, want %v", rec.statuses, want)
+	}
+
+	for i, status := range rec.statuses {
+		if status != want[i] {
+			t.Errorf("call %d recorded status %d, want %d", i, status, want[i])
+		}
+	}
+}
+
+func TestEndpointFrom(t *testing.T) {
+	ctx := context.Background()
+	if got := metrics.EndpointFrom(ctx); got != metrics.EndpointOther {
+		t.Errorf("got %s for an untagged context", got)
+	}
+
+	if got := metrics.EndpointFrom(metrics.WithEndpoint(ctx, metrics.EndpointListOrders)); got != metrics.EndpointListOrders {
+		t.Errorf("got %s, want %s", got, metrics.EndpointListOrders)
+	}
+}
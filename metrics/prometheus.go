@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusRecorder is a Recorder and a prometheus.Collector, register it
+// with a registry for its metrics to be exported.
+type PrometheusRecorder struct {
+	requests         *prometheus.CounterVec
+	latency          *prometheus.HistogramVec
+	retries          *prometheus.CounterVec
+	pages            *prometheus.CounterVec
+	items            *prometheus.CounterVec
+	cache            *prometheus.CounterVec
+	coinbase         *prometheus.CounterVec
+	coinbaseDuration prometheus.Histogram
+}
+
+var _ prometheus.Collector = (*PrometheusRecorder)(nil)
+
+// NewPrometheusRecorder prefixes every metric with namespace, e.g.
+// imx_requests_total.
+func NewPrometheusRecorder(namespace string) *PrometheusRecorder {
+	return &PrometheusRecorder{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "API requests by endpoint, backend and HTTP status, 0 when no response was received.",
+		}, []string{"endpoint", "backend", "status"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "API request latency.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint", "backend"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "retries_total",
+			Help:      "Requests retried after a failure.",
+		}, []string{"endpoint"}),
+		pages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pages_total",
+			Help:      "Pages of results fetched.",
+		}, []string{"endpoint"}),
+		items: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "items_total",
+			Help:      "Items returned across all pages.",
+		}, []string{"endpoint"}),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_requests_total",
+			Help:      "Cache lookups by result, hit or miss.",
+		}, []string{"endpoint", "result"}),
+		coinbase: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "coinbase_fetches_total",
+			Help:      "Coinbase spot price fetches by pair and result, ok or error.",
+		}, []string{"pair", "result"}),
+		coinbaseDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "coinbase_fetch_duration_seconds",
+			Help:      "Coinbase spot price fetch latency.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+	}
+}
+
+func (p *PrometheusRecorder) collectors() []prometheus.Collector {
+	return []prometheus.Collector{p.requests, p.latency, p.retries, p.pages, p.items, p.cache, p.coinbase, p.coinbaseDuration}
+}
+
+func (p *PrometheusRecorder) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range p.collectors() {
+		c.Describe(ch)
+	}
+}
+
+func (p *PrometheusRecorder) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range p.collectors() {
+		c.Collect(ch)
+	}
+}
+
+func (p *PrometheusRecorder) Request(endpoint, backend string, status int, duration time.Duration) {
+	p.requests.WithLabelValues(endpoint, backend, strconv.Itoa(status)).Inc()
+	p.latency.WithLabelValues(endpoint, backend).Observe(duration.Seconds())
+}
+
+func (p *PrometheusRecorder) Retry(endpoint string) {
+	p.retries.WithLabelValues(endpoint).Inc()
+}
+
+func (p *PrometheusRecorder) Page(endpoint string, items int) {
+	p.pages.WithLabelValues(endpoint).Inc()
+	p.items.WithLabelValues(endpoint).Add(float64(items))
+}
+
+func (p *PrometheusRecorder) CacheHit(endpoint string) {
+	p.cache.WithLabelValues(endpoint, "hit").Inc()
+}
+
+func (p *PrometheusRecorder) CacheMiss(endpoint string) {
+	p.cache.WithLabelValues(endpoint, "miss").Inc()
+}
+
+func (p *PrometheusRecorder) CoinbaseFetch(pair string, duration time.Duration, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+
+	p.coinbase.WithLabelValues(pair, result).Inc()
+	p.coinbaseDuration.Observe(duration.Seconds())
+}
@@ -0,0 +1,169 @@
+// Package notify delivers watch events to webhooks and chat services.
+//
+//	n := notify.New(notify.Config{
+//		Targets:  []notify.Target{{Sink: notify.NewDiscordSink(webhookURL)}},
+//		Renderer: notify.Renderer{Fiat: coinbase.FiatUSD},
+//	})
+//	go w.Run(ctx)
+//	n.Run(ctx, w.Events())
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/deadloct/immutablex-go-lib/output"
+	"github.com/deadloct/immutablex-go-lib/watch"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+	log "github.com/sirupsen/logrus"
+)
+
+type Field struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Message is a rendered event, formatted by each sink for its service.
+type Message struct {
+	Event  string      `json:"event"`
+	Title  string      `json:"title"`
+	URL    string      `json:"url,omitempty"`
+	Image  string      `json:"image,omitempty"`
+	Fields []Field     `json:"fields,omitempty"`
+	Data   watch.Event `json:"data"`
+}
+
+// Text renders the message as plain text lines.
+func (m Message) Text() string {
+	text := m.Title
+	for _, f := range m.Fields {
+		text += fmt.Sprintf("\n%s: %s", f.Name, f.Value)
+	}
+
+	if m.URL != "" {
+		text += "\n" + m.URL
+	}
+
+	return text
+}
+
+// Renderer turns events into messages with the same data the printers show.
+type Renderer struct {
+	Fiat   coinbase.FiatSymbol
+	Locale string
+	// Assets is used to look up the names of tokens in orders. When nil,
+	// tokens are named by their address and ID.
+	Assets assets.Client
+	// Prices converts order prices to Fiat, defaulting to
+	// coinbase.GetPriceClient().
+	Prices coinbase.PriceClient
+}
+
+func (r *Renderer) Render(ctx context.Context, e watch.Event) Message {
+	msg := Message{Event: e.Name(), Data: e}
+
+	switch e := e.(type) {
+	case watch.OrderCreated:
+		r.renderOrder(ctx, &msg, "New listing", e.Order)
+	case watch.OrderFilled:
+		r.renderOrder(ctx, &msg, "Sold", e.Order)
+	case watch.OrderCancelled:
+		r.renderOrder(ctx, &msg, "Listing cancelled", e.Order)
+	case watch.OrderExpired:
+		r.renderOrder(ctx, &msg, "Listing expired", e.Order)
+	case watch.OrderUpdated:
+		r.renderOrder(ctx, &msg, "Order updated", e.Order)
+	case watch.AssetCreated:
+		renderAsset(&msg, "Minted", e.Asset)
+	case watch.AssetTransferred:
+		renderAsset(&msg, "Transferred", e.Asset)
+		msg.Fields = append(msg.Fields, Field{"From", e.From}, Field{"To", e.To})
+	case watch.AssetBurned:
+		renderAsset(&msg, "Burned", e.Asset)
+	case watch.AssetUpdated:
+		renderAsset(&msg, "Asset updated", e.Asset)
+	default:
+		msg.Title = e.Name()
+	}
+
+	return msg
+}
+
+func (r *Renderer) renderOrder(ctx context.Context, msg *Message, action string, order api.Order) {
+	msg.Title = action + ": " + r.tokenName(ctx, order)
+	msg.URL = output.Immutascan("order", order.OrderId)
+
+	if order.Buy.Data.Decimals != nil {
+		price := orders.GetPrice(order)
+		symbol := orders.CurrencySymbol(order)
+		value := output.FormatPrice(price, string(symbol))
+
+		fiat := r.Fiat
+		if fiat == "" {
+			fiat = coinbase.FiatUSD
+		}
+
+		prices := r.Prices
+		if prices == nil {
+			prices = coinbase.GetPriceClient()
+		}
+
+		if spot := prices.RetrieveSpotPrice(symbol, fiat); spot > 0 {
+			value += " (" + coinbase.FormatFiat(price*spot, fiat, r.Locale) + ")"
+		}
+
+		msg.Fields = append(msg.Fields, Field{"Price With Fees", value})
+	}
+
+	msg.Fields = append(msg.Fields,
+		Field{"Status", order.Status},
+		Field{"User", order.User},
+		Field{"Date", order.GetUpdatedTimestamp()},
+	)
+}
+
+// tokenName names the token an order sells, or bids on for buy orders.
+func (r *Renderer) tokenName(ctx context.Context, order api.Order) string {
+	data := order.GetSell().Data
+	if data.GetTokenId() == "" {
+		data = order.GetBuy().Data
+	}
+
+	if data.GetTokenId() == "" {
+		return fmt.Sprintf("order %d", order.OrderId)
+	}
+
+	if r.Assets != nil {
+		asset, err := r.Assets.GetAsset(ctx, data.GetTokenAddress(), data.GetTokenId(), false)
+		if err == nil && asset.GetName() != "" {
+			return asset.GetName()
+		}
+
+		if err != nil {
+			log.Debugf("could not look up name of %s #%s: %v", data.GetTokenAddress(), data.GetTokenId(), err)
+		}
+	}
+
+	return output.TruncateAddress(data.GetTokenAddress()) + " #" + data.GetTokenId()
+}
+
+func renderAsset(msg *Message, action string, asset api.AssetWithOrders) {
+	name := asset.GetName()
+	if name == "" {
+		name = output.TruncateAddress(asset.TokenAddress) + " #" + asset.TokenId
+	}
+
+	msg.Title = action + ": " + name
+	msg.URL = output.Immutascan("address", asset.TokenAddress, asset.TokenId)
+	if url := asset.ImageUrl.Get(); url != nil {
+		msg.Image = *url
+	}
+
+	msg.Fields = append(msg.Fields,
+		Field{"Status", asset.Status},
+		Field{"Owner", asset.User},
+	)
+}
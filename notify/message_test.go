@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/imxtest"
+	"github.com/deadloct/immutablex-go-lib/watch"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+// imxOrder is a hero listed for 25 IMX.
+const imxOrder = `{
+	"order_id": 10,
+	"status": "active",
+	"user": "0x2222222222222222222222222222222222222222",
+	"sell": {"type": "ERC721", "data": {"token_address": "0x6465ef3009f3c474774f4afb607a5d600ea71d95", "token_id": "4", "quantity": "1"}},
+	"buy": {"type": "ERC20", "data": {"token_address": "0xf57e7e7c23978c3caec3c3548e3d615c346e79ff", "decimals": 18, "symbol": "IMX", "quantity": "25000000000000000000", "quantity_with_fees": ""}}
+}`
+
+func priceField(msg Message) string {
+	for _, field := range msg.Fields {
+		if field.Name == "Price With Fees" {
+			return field.Value
+		}
+	}
+
+	return ""
+}
+
+func TestRendererUsesItsPrices(t *testing.T) {
+	f := imxtest.DefaultFixtures()
+	prices := imxtest.NewPriceClient(map[coinbase.SpotPair]float64{
+		{Crypto: "ETH", Fiat: coinbase.FiatEUR}: 2000,
+	})
+
+	r := Renderer{Fiat: coinbase.FiatEUR, Locale: "de-DE", Prices: prices}
+	msg := r.Render(context.Background(), watch.OrderCreated{Order: f.Orders[1]})
+
+	if price, want := priceField(msg), "0.5 ETH (1.000,00 €)"; price != want {
+		t.Errorf("got price %q, want %q", price, want)
+	}
+
+	if n := len(prices.CallsTo("RetrieveSpotPrice")); n != 1 {
+		t.Errorf("RetrieveSpotPrice called %d times, want 1", n)
+	}
+}
+
+func TestRendererPricesERC20Orders(t *testing.T) {
+	var order api.Order
+	if err := json.Unmarshal([]byte(imxOrder), &order); err != nil {
+		t.Fatal(err)
+	}
+
+	prices := imxtest.NewPriceClient(map[coinbase.SpotPair]float64{
+		{Crypto: coinbase.CryptoIMX, Fiat: coinbase.FiatUSD}: 0.8,
+	})
+
+	r := Renderer{Prices: prices}
+	msg := r.Render(context.Background(), watch.OrderFilled{Order: order})
+
+	if price, want := priceField(msg), "25 IMX ($20.00)"; price != want {
+		t.Errorf("got price %q, want %q", price, want)
+	}
+}
@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/metrics"
+	"github.com/deadloct/immutablex-go-lib/watch"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+const (
+	DefaultMaxRetries = 3
+	DefaultRetryWait  = time.Second
+	DefaultQueueSize  = 100
+
+	// Default per target rate limits, below the webhook limits of the chat
+	// services.
+	DefaultRequestsPerSecond = 1
+	DefaultRequestBurst      = 5
+)
+
+// Target is a sink with its own rate limit and event filter.
+type Target struct {
+	Sink Sink
+	// Limiter defaults to DefaultRequestsPerSecond with DefaultRequestBurst.
+	Limiter *rate.Limiter
+	// Events are the event names sent to the sink, or all when empty.
+	Events []string
+}
+
+type Config struct {
+	Targets  []Target
+	Renderer Renderer
+	// MaxRetries defaults to DefaultMaxRetries; a negative value disables
+	// retries. RetryWait is the first backoff, doubled on each retry.
+	MaxRetries int
+	RetryWait  time.Duration
+}
+
+// Notifier renders events once and delivers them to every target. Each target
+// has its own queue, so a slow or failing target doesn't hold up the others.
+type Notifier struct {
+	cfg     Config
+	targets []Target
+}
+
+func New(cfg Config) *Notifier {
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	} else if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+
+	if cfg.RetryWait <= 0 {
+		cfg.RetryWait = DefaultRetryWait
+	}
+
+	targets := make([]Target, len(cfg.Targets))
+	for i, t := range cfg.Targets {
+		if t.Limiter == nil {
+			t.Limiter = rate.NewLimiter(DefaultRequestsPerSecond, DefaultRequestBurst)
+		}
+		targets[i] = t
+	}
+
+	return &Notifier{cfg: cfg, targets: targets}
+}
+
+// Run delivers events until the channel is closed or ctx is done, then waits
+// for queued messages to be sent.
+func (n *Notifier) Run(ctx context.Context, events <-chan watch.Event) error {
+	var wg sync.WaitGroup
+	queues := make([]chan Message, len(n.targets))
+	for i := range n.targets {
+		queues[i] = make(chan Message, DefaultQueueSize)
+
+		wg.Add(1)
+		go func(t Target, queue <-chan Message) {
+			defer wg.Done()
+			for msg := range queue {
+				if err := n.send(ctx, t, msg); err != nil {
+					log.Errorf("could not deliver %s notification: %v", msg.Event, err)
+				}
+			}
+		}(n.targets[i], queues[i])
+	}
+
+	defer func() {
+		for _, q := range queues {
+			close(q)
+		}
+		wg.Wait()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			msg := n.cfg.Renderer.Render(ctx, e)
+			for i, t := range n.targets {
+				if !wants(t, msg.Event) {
+					continue
+				}
+
+				select {
+				case queues[i] <- msg:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+	}
+}
+
+// Notify renders an event and sends it to every target, returning the first
+// error.
+func (n *Notifier) Notify(ctx context.Context, e watch.Event) error {
+	msg := n.cfg.Renderer.Render(ctx, e)
+
+	var firstErr error
+	for _, t := range n.targets {
+		if !wants(t, msg.Event) {
+			continue
+		}
+
+		if err := n.send(ctx, t, msg); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// send delivers a message, retrying network errors, 429s and 5xx responses
+// with exponential backoff or the server's Retry-After.
+func (n *Notifier) send(ctx context.Context, t Target, msg Message) error {
+	wait := n.cfg.RetryWait
+	for attempt := 0; ; attempt++ {
+		if err := t.Limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		err := t.Sink.Send(ctx, msg)
+		if err == nil || attempt >= n.cfg.MaxRetries || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		delay := wait
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			delay = statusErr.RetryAfter
+		}
+
+		log.Debugf("retrying %s notification in %s: %v", msg.Event, delay, err)
+		metrics.GetRecorder().Retry(metrics.EndpointNotify)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		wait *= 2
+	}
+}
+
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+
+	return true
+}
+
+func wants(t Target, event string) bool {
+	if len(t.Events) == 0 {
+		return true
+	}
+
+	for _, e := range t.Events {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
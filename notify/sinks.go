@@ -0,0 +1,239 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader          = "X-Signature-256"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+
+	DefaultTelegramAPIURL = "https://api.telegram.org"
+)
+
+// Sink delivers a message to one destination.
+type Sink interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// StatusError is returned by sinks when the destination answers with a non 2xx
+// status. RetryAfter is set from the Retry-After header when present.
+type StatusError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("notification failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}, headers map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("could not encode notification: %w", err)
+	}
+
+	return post(ctx, client, url, body, headers)
+}
+
+func post(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	statusErr := &StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(data))}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		statusErr.RetryAfter = time.Duration(secs) * time.Second
+	}
+
+	return statusErr
+}
+
+// WebhookSink posts messages as JSON to any URL. When Secret is set, the body
+// is signed with HMAC-SHA256 over "<timestamp>.<body>"; see VerifySignature.
+type WebhookSink struct {
+	URL        string
+	Secret     string
+	HTTPClient *http.Client
+}
+
+func NewWebhookSink(url, secret string) *WebhookSink {
+	return &WebhookSink{URL: url, Secret: secret}
+}
+
+func (s *WebhookSink) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("could not encode notification: %w", err)
+	}
+
+	headers := map[string]string{}
+	if s.Secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		headers[SignatureTimestampHeader] = ts
+		headers[SignatureHeader] = "sha256=" + sign(s.Secret, ts, body)
+	}
+
+	return post(ctx, s.HTTPClient, s.URL, body, headers)
+}
+
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks a WebhookSink signature, for use by receivers.
+func VerifySignature(secret, timestamp, signature string, body []byte) bool {
+	expected := "sha256=" + sign(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// DiscordSink posts messages as embeds to a Discord webhook URL.
+type DiscordSink struct {
+	WebhookURL string
+	HTTPClient *http.Client
+}
+
+func NewDiscordSink(webhookURL string) *DiscordSink {
+	return &DiscordSink{WebhookURL: webhookURL}
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordImage struct {
+	URL string `json:"url"`
+}
+
+type discordEmbed struct {
+	Title     string         `json:"title"`
+	URL       string         `json:"url,omitempty"`
+	Fields    []discordField `json:"fields,omitempty"`
+	Thumbnail *discordImage  `json:"thumbnail,omitempty"`
+}
+
+func (s *DiscordSink) Send(ctx context.Context, msg Message) error {
+	embed := discordEmbed{Title: msg.Title, URL: msg.URL}
+	for _, f := range msg.Fields {
+		embed.Fields = append(embed.Fields, discordField{Name: f.Name, Value: orDash(f.Value), Inline: true})
+	}
+
+	if msg.Image != "" {
+		embed.Thumbnail = &discordImage{URL: msg.Image}
+	}
+
+	payload := map[string]interface{}{"embeds": []discordEmbed{embed}}
+	return postJSON(ctx, s.HTTPClient, s.WebhookURL, payload, nil)
+}
+
+// SlackSink posts messages to a Slack incoming webhook URL.
+type SlackSink struct {
+	WebhookURL string
+	HTTPClient *http.Client
+}
+
+func NewSlackSink(webhookURL string) *SlackSink {
+	return &SlackSink{WebhookURL: webhookURL}
+}
+
+func (s *SlackSink) Send(ctx context.Context, msg Message) error {
+	title := msg.Title
+	if msg.URL != "" {
+		title = fmt.Sprintf("<%s|%s>", msg.URL, msg.Title)
+	}
+
+	blocks := []map[string]interface{}{{
+		"type": "section",
+		"text": map[string]string{"type": "mrkdwn", "text": "*" + title + "*"},
+	}}
+
+	if len(msg.Fields) > 0 {
+		var fields []map[string]string
+		for _, f := range msg.Fields {
+			fields = append(fields, map[string]string{"type": "mrkdwn", "text": fmt.Sprintf("*%s*\n%s", f.Name, orDash(f.Value))})
+		}
+		blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
+	}
+
+	payload := map[string]interface{}{"text": msg.Title, "blocks": blocks}
+	return postJSON(ctx, s.HTTPClient, s.WebhookURL, payload, nil)
+}
+
+// TelegramSink sends messages to a chat through a Telegram bot. APIURL can be
+// pointed at a local server for testing.
+type TelegramSink struct {
+	APIURL     string
+	Token      string
+	ChatID     string
+	HTTPClient *http.Client
+}
+
+func NewTelegramSink(token, chatID string) *TelegramSink {
+	return &TelegramSink{APIURL: DefaultTelegramAPIURL, Token: token, ChatID: chatID}
+}
+
+func (s *TelegramSink) Send(ctx context.Context, msg Message) error {
+	payload := map[string]interface{}{
+		"chat_id": s.ChatID,
+		"text":    msg.Text(),
+	}
+
+	endpoint := strings.TrimSuffix(s.APIURL, "/") + "/bot" + s.Token + "/sendMessage"
+	err := postJSON(ctx, s.HTTPClient, endpoint, payload, nil)
+
+	// The token is part of the URL, which transport errors include.
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && s.Token != "" {
+		urlErr.URL = strings.ReplaceAll(urlErr.URL, s.Token, "<token>")
+	}
+
+	return err
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
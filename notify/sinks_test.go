@@ -0,0 +1,26 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTelegramSinkErrorsHideToken(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	sink := NewTelegramSink("123456:secret-token", "42")
+	sink.APIURL = srv.URL
+
+	err := sink.Send(context.Background(), Message{Title: "test"})
+	if err == nil {
+		t.Fatal("expected an error from a closed server")
+	}
+
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("error leaks the bot token: %v", err)
+	}
+}
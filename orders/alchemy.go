@@ -2,27 +2,53 @@ package orders
 
 import (
 	"context"
-	"math"
+	"net/http"
+	"time"
 
 	"github.com/deadloct/immutablex-go-lib/collections"
 	"github.com/deadloct/immutablex-go-lib/imx"
+	"github.com/deadloct/immutablex-go-lib/metrics"
+	"github.com/deadloct/immutablex-go-lib/utils"
 	"github.com/immutable/imx-core-sdk-golang/imx/api"
 	log "github.com/sirupsen/logrus"
 )
 
 type AlchemyClientConfig struct {
 	alchemyKey string
+
+	// HTTPClient is passed to the SDK, its default is used when nil.
+	HTTPClient *http.Client
+
+	// Client replaces the SDK client wrapper, e.g. with a fake in tests.
+	Client imx.ClientWrapper
+
+	// URL is the public API single orders are fetched from,
+	// utils.DefaultImmutableAPIURL when empty.
+	URL string
 }
 
 type AlchemyClient struct {
-	client    imx.ClientWrapper
-	shortcuts collections.Shortcuts
+	client imx.ClientWrapper
+
+	// Single orders are fetched from the public API, which doesn't need the
+	// Alchemy key.
+	rest *RESTClient
 }
 
 func NewAlchemyClient(cfg AlchemyClientConfig) *AlchemyClient {
+	var client imx.ClientWrapper = cfg.Client
+	if client == nil {
+		client = imx.NewClientWithHTTPClient(cfg.alchemyKey, cfg.HTTPClient)
+	}
+
+	url := cfg.URL
+	if url == "" {
+		url = utils.DefaultImmutableAPIURL
+	}
+
 	return &AlchemyClient{
-		client:    imx.NewClient(cfg.alchemyKey),
-		shortcuts: collections.NewShortcuts(),
+		client: client,
+		rest:   NewRESTClient(RESTClientConfig{URL: url, HTTPClient: cfg.HTTPClient}),
 	}
 }
 
@@ -34,28 +60,31 @@ func (c *AlchemyClient) Stop() {
 	c.client.Stop()
 }
 
-func (c *AlchemyClient) GetOrder() (api.Order, error) {
-	return api.Order{}, nil
+func (c *AlchemyClient) GetOrder(ctx context.Context, orderID string, includeFees bool) (*api.Order, error) {
+	return c.rest.GetOrder(ctx, orderID, includeFees)
 }
 
 func (c *AlchemyClient) ListOrders(ctx context.Context, cfg *ListOrdersConfig) ([]api.Order, error) {
-	req := c.getAPIListOrdersRequest(ctx, cfg)
+	req := c.getAPIListOrdersRequest(metrics.WithEndpoint(ctx, metrics.EndpointListOrders), cfg)
+	start := time.Now()
 	resp, err := c.client.GetClient().ListOrders(req)
+	metrics.ObserveCall(metrics.EndpointListOrders, start, err)
 	if err != nil {
 		return nil, err
 	}
 
+	metrics.GetRecorder().Page(metrics.EndpointListOrders, len(resp.Result))
+
 	if len(resp.Result) == 0 {
 		return cfg.Orders, nil
 	}
 
 	cfg.Orders = append(cfg.Orders, resp.Result...)
-	cfg.Orders = cfg.Orders[:int(math.Min(float64(len(cfg.Orders)), float64(cfg.PageSize)))]
 	cfg.Cursor = resp.Cursor
 
-	first := *resp.Result[0].UpdatedTimestamp.Get()
-	last := *resp.Result[len(resp.Result)-1].UpdatedTimestamp.Get()
-	log.Debugf("fetched %v collections from %v to %v", len(resp.Result), first, last)
+	first := resp.Result[0].GetUpdatedTimestamp()
+	last := resp.Result[len(resp.Result)-1].GetUpdatedTimestamp()
+	log.Debugf("fetched %v orders from %v to %v", len(resp.Result), first, last)
 
 	getMore := len(cfg.Orders) < cfg.PageSize || cfg.PageSize == 0
 	if resp.Remaining > 0 && getMore {
@@ -73,7 +102,7 @@ func (c *AlchemyClient) getAPIListOrdersRequest(ctx context.Context, cfg *ListOr
 	}
 
 	if cfg.AuxiliaryFeeRecipients != "" {
-		req = req.AuxiliaryFeeRecipients(cfg.AuxiliaryFeeRecipients)
+		req = req.AuxiliaryFeeRecipients(collections.ResolveWallets("auxiliary fee recipients", cfg.AuxiliaryFeeRecipients))
 	}
 
 	if cfg.BuyAssetID != "" {
@@ -93,13 +122,7 @@ func (c *AlchemyClient) getAPIListOrdersRequest(ctx context.Context, cfg *ListOr
 	}
 
 	if cfg.BuyTokenAddress != "" {
-		buyAddr := cfg.BuyTokenAddress
-		if v := c.shortcuts.GetShortcutByName(buyAddr); v != nil {
-			buyAddr = v.Addr
-		}
-
-		log.Debugf("using buy token address %s (provided value: %s)", buyAddr, cfg.BuyTokenAddress)
-		req = req.BuyTokenAddress(buyAddr)
+		req = req.BuyTokenAddress(collections.ResolveCollection("buy token address", cfg.BuyTokenAddress))
 	}
 
 	if cfg.BuyTokenID != "" {
@@ -114,6 +137,10 @@ func (c *AlchemyClient) getAPIListOrdersRequest(ctx context.Context, cfg *ListOr
 		req = req.BuyTokenType(cfg.BuyTokenType)
 	}
 
+	if cfg.Cursor != "" {
+		req = req.Cursor(cfg.Cursor)
+	}
+
 	if cfg.Direction != "" {
 		req = req.Direction(cfg.Direction)
 	}
@@ -135,7 +162,7 @@ func (c *AlchemyClient) getAPIListOrdersRequest(ctx context.Context, cfg *ListOr
 	}
 
 	if cfg.PageSize > 0 {
-		req = req.PageSize(int32(cfg.PageSize))
+		req = req.PageSize(int32(cfg.PageSize - len(cfg.Orders)))
 	}
 
 	if cfg.SellAssetID != "" {
@@ -155,13 +182,7 @@ func (c *AlchemyClient) getAPIListOrdersRequest(ctx context.Context, cfg *ListOr
 	}
 
 	if cfg.SellTokenAddress != "" {
-		sellAddr := cfg.SellTokenAddress
-		if v := c.shortcuts.GetShortcutByName(sellAddr); v != nil {
-			sellAddr = v.Addr
-		}
-
-		log.Debugf("using sell token address %s (provided value: %s)", sellAddr, cfg.SellTokenAddress)
-		req = req.SellTokenAddress(sellAddr)
+		req = req.SellTokenAddress(collections.ResolveCollection("sell token address", cfg.SellTokenAddress))
 	}
 
 	if cfg.SellTokenID != "" {
@@ -189,7 +210,7 @@ func (c *AlchemyClient) getAPIListOrdersRequest(ctx context.Context, cfg *ListOr
 	}
 
 	if cfg.User != "" {
-		req = req.User(cfg.User)
+		req = req.User(collections.ResolveWallet("user", cfg.User))
 	}
 
 	return &req
@@ -3,6 +3,7 @@ package orders
 import (
 	"context"
 	"log"
+	"net/http"
 
 	"github.com/deadloct/immutablex-go-lib/utils"
 	"github.com/immutable/imx-core-sdk-golang/imx/api"
@@ -44,16 +45,23 @@ type ListOrdersConfig struct {
 type Client interface {
 	Start() error
 	Stop()
-	GetOrder() (api.Order, error)
+	GetOrder(ctx context.Context, orderID string, includeFees bool) (*api.Order, error)
 	ListOrders(ctx context.Context, cfg *ListOrdersConfig) ([]api.Order, error)
 }
 
 func NewClientConfig(alchemyKey string) interface{} {
+	return NewClientConfigWithHTTPClient(alchemyKey, nil)
+}
+
+// NewClientConfigWithHTTPClient is NewClientConfig with the HTTP client every
+// request is sent with, the default is used when nil. Wrap its transport in a
+// utils.RetryTransport to retry requests the API is too busy for.
+func NewClientConfigWithHTTPClient(alchemyKey string, httpClient *http.Client) interface{} {
 	if alchemyKey == "" {
-		return RESTClientConfig{URL: utils.DefaultImmutableAPIURL}
+		return RESTClientConfig{URL: utils.DefaultImmutableAPIURL, HTTPClient: httpClient}
 	}
 
-	return AlchemyClientConfig{alchemyKey: alchemyKey}
+	return AlchemyClientConfig{alchemyKey: alchemyKey, HTTPClient: httpClient}
 }
 
 func NewClient(cfg interface{}) Client {
@@ -1,58 +1,222 @@
 package orders
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"math"
-	"strconv"
+	"io"
+	"math/big"
+	"os"
 	"strings"
 
 	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/output"
 	"github.com/deadloct/immutablex-go-lib/utils"
 	"github.com/immutable/imx-core-sdk-golang/imx/api"
 	log "github.com/sirupsen/logrus"
 )
 
-func getPrice(order api.Order) float64 {
-	amount, err := strconv.Atoi(order.GetBuy().Data.QuantityWithFees)
-	if err != nil {
-		return 0
+// DefaultFields are the fields used by the tabular output formats when none
+// are selected.
+var DefaultFields = []string{"order_id", "status", "price", "currency", "fiat_price", "user", "updated_timestamp", "immutascan_url"}
+
+// GetPrice returns the order's buy price including fees, in units of the buy
+// currency (e.g. ETH rather than wei), or 0 when it can't be read.
+func GetPrice(order api.Order) float64 {
+	price, _ := OrderPrice(order, true)
+	return price
+}
+
+// OrderPrice returns the order's buy price in units of the buy currency, with
+// or without fees. Orders fetched without fees fall back to the quantity
+// without them. ok is false when the quantity or decimals are missing or
+// invalid.
+func OrderPrice(order api.Order, withFees bool) (float64, bool) {
+	data := order.GetBuy().Data
+	if data.Decimals == nil || *data.Decimals < 0 {
+		return 0, false
 	}
 
-	decimals := int(*order.GetBuy().Data.Decimals)
-	return float64(amount) * math.Pow10(-1*decimals)
+	quantity := data.Quantity
+	if withFees && data.QuantityWithFees != "" {
+		quantity = data.QuantityWithFees
+	}
+
+	// Quantities are in the token's smallest unit, wei for ETH, so they
+	// overflow an int64 above about 9.2 ETH.
+	amount, ok := new(big.Int).SetString(quantity, 10)
+	if !ok {
+		return 0, false
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(*data.Decimals)), nil)
+	price, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), new(big.Float).SetInt(scale)).Float64()
+	return price, true
 }
 
-func PrintOrderJSON(order api.Order) {
+// CurrencySymbol returns the symbol of the currency the order is priced in,
+// e.g. ETH, or the token symbol such as IMX or USDC for ERC20 prices.
+func CurrencySymbol(order api.Order) coinbase.CryptoSymbol {
+	buy := order.GetBuy()
+	if symbol := buy.Data.GetSymbol(); symbol != "" {
+		return coinbase.CryptoSymbol(strings.ToUpper(symbol))
+	}
+
+	return coinbase.CryptoSymbol(strings.ToUpper(buy.Type))
+}
+
+func WriteOrderJSON(w io.Writer, order api.Order) error {
 	data, err := json.MarshalIndent(order, "", "  ")
 	if err != nil {
-		log.Debugf("could not convert asset to json: %v\nasset: %#v\n", err, order)
-		return
+		return fmt.Errorf("could not convert order to json: %w", err)
 	}
 
-	fmt.Println(string(data))
+	_, err = fmt.Fprintln(w, string(data))
+	return err
 }
 
-func PrintOrderNormal(order api.Order) {
-	url := strings.Join([]string{utils.ImmutascanURL, "order", fmt.Sprint(order.OrderId)}, "/")
-	price := getPrice(order)
-	symbol := coinbase.CryptoSymbol(order.GetBuy().Type)
-	fiatPrice := price * coinbase.GetCoinbaseClientInstance().RetrieveSpotPrice(symbol, coinbase.FiatUSD)
-	fmt.Printf(`Order:
+func PrintOrderJSON(order api.Order) {
+	if err := WriteOrderJSON(os.Stdout, order); err != nil {
+		log.Errorf("could not print order: %v", err)
+	}
+}
+
+func getOrderURL(order api.Order) string {
+	return strings.Join([]string{utils.ImmutascanURL, "order", fmt.Sprint(order.OrderId)}, "/")
+}
+
+func WriteOrderNormal(w io.Writer, order api.Order, fiat coinbase.FiatSymbol, locale string) error {
+	if fiat == "" {
+		fiat = coinbase.FiatUSD
+	}
+
+	url := getOrderURL(order)
+	price := GetPrice(order)
+	symbol := CurrencySymbol(order)
+	fiatPrice := price * coinbase.GetPriceClient().RetrieveSpotPrice(symbol, fiat)
+	_, err := fmt.Fprintf(w, `Order:
 - Status: %s
-- Price With Fees: %f %s / %.2f %s
+- Price With Fees: %f %s / %s
 - User: %s
 - Date: %s
-- Immutascan: %s%s`, order.Status, price, symbol, fiatPrice, coinbase.FiatUSD, order.User, order.GetUpdatedTimestamp(), url, "\n\n")
+- Immutascan: %s%s`, order.Status, price, symbol, coinbase.FormatFiat(fiatPrice, fiat, locale), order.User, order.GetUpdatedTimestamp(), url, "\n\n")
+	return err
+}
+
+// PrintOrderNormal prints the order with its price in USD.
+func PrintOrderNormal(order api.Order) {
+	PrintOrderNormalWithFiat(order, coinbase.FiatUSD, coinbase.DefaultLocale)
+}
+
+// PrintOrderNormalWithFiat prints the order with its price in fiat, formatted
+// for locale.
+func PrintOrderNormalWithFiat(order api.Order, fiat coinbase.FiatSymbol, locale string) {
+	if err := WriteOrderNormal(os.Stdout, order, fiat, locale); err != nil {
+		log.Errorf("could not print order: %v", err)
+	}
+}
+
+func warmSpotPrices(orders []api.Order, fiat coinbase.FiatSymbol) {
+	pairs := make([]coinbase.SpotPair, 0, len(orders))
+	for _, o := range orders {
+		pairs = append(pairs, coinbase.SpotPair{
+			Crypto: CurrencySymbol(o),
+			Fiat:   fiat,
+		})
+	}
+
+	coinbase.GetPriceClient().RetrieveSpotPrices(context.Background(), pairs)
 }
 
-func PrintOrders(orders []api.Order, output string) {
+func writeFormatted(w io.Writer, orders []api.Order, format string, fiat coinbase.FiatSymbol, locale string) error {
+	if fiat == "" {
+		fiat = coinbase.FiatUSD
+	}
+
+	f, fields, err := output.New(format)
+	if err != nil {
+		return err
+	}
+
+	selected := fields
+	if len(selected) == 0 {
+		selected = DefaultFields
+	}
+
+	needsFiat := false
+	for _, f := range selected {
+		if strings.HasPrefix(f, "fiat") {
+			needsFiat = true
+		}
+	}
+
+	if needsFiat {
+		warmSpotPrices(orders, fiat)
+	}
+
+	items := make([]interface{}, len(orders))
+	for i := range orders {
+		items[i] = orders[i]
+	}
+
+	extra := func(i int) map[string]interface{} {
+		o := orders[i]
+		price := GetPrice(o)
+		symbol := CurrencySymbol(o)
+		values := map[string]interface{}{
+			"price":          price,
+			"currency":       string(symbol),
+			"immutascan_url": getOrderURL(o),
+		}
+
+		if needsFiat {
+			fiatPrice := price * coinbase.GetPriceClient().RetrieveSpotPrice(symbol, fiat)
+			values["fiat_price"] = coinbase.FormatFiat(fiatPrice, fiat, locale)
+			values["fiat_amount"] = fiatPrice
+			values["fiat_currency"] = string(fiat)
+		}
+
+		return values
+	}
+
+	doc, err := output.NewDocument(items, fields, DefaultFields, extra)
+	if err != nil {
+		return err
+	}
+
+	return f.Format(w, doc)
+}
+
+// WriteOrders writes the orders to w in the given format: "standard" (the
+// default) or any format registered with the output package, optionally
+// followed by the fields to include, e.g. "table=order_id,price,fiat_price", or
+// a template such as "template={{price .price .currency}} {{.fiat_price}}".
+// Fiat prices use the ISO-4217 currency fiat (USD if empty) formatted for
+// locale (e.g. "de-DE").
+func WriteOrders(w io.Writer, orders []api.Order, format string, fiat coinbase.FiatSymbol, locale string) error {
+	if !output.IsStandard(format) {
+		return writeFormatted(w, orders, format, fiat, locale)
+	}
+
+	warmSpotPrices(orders, fiat)
 	for _, o := range orders {
-		switch strings.ToLower(output) {
-		case "json":
-			PrintOrderJSON(o)
-		default:
-			PrintOrderNormal(o)
+		if err := WriteOrderNormal(w, o, fiat, locale); err != nil {
+			return err
 		}
 	}
+
+	return nil
+}
+
+// PrintOrders prints the orders to stdout with prices in USD, see WriteOrders.
+func PrintOrders(orders []api.Order, format string) {
+	PrintOrdersWithFiat(orders, format, coinbase.FiatUSD, coinbase.DefaultLocale)
+}
+
+// PrintOrdersWithFiat prints the orders to stdout with prices in fiat,
+// formatted for locale, see WriteOrders.
+func PrintOrdersWithFiat(orders []api.Order, format string, fiat coinbase.FiatSymbol, locale string) {
+	if err := WriteOrders(os.Stdout, orders, format, fiat, locale); err != nil {
+		log.Errorf("could not print orders: %v", err)
+	}
 }
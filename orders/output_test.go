@@ -0,0 +1,123 @@
+package orders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+func buyOrder(t *testing.T, buy string) api.Order {
+	t.Helper()
+
+	var order api.Order
+	if err := json.Unmarshal([]byte(`{"order_id": 1, "buy": `+buy+`}`), &order); err != nil {
+		t.Fatal(err)
+	}
+
+	return order
+}
+
+func TestOrderPrice(t *testing.T) {
+	tests := map[string]struct {
+		buy      string
+		withFees bool
+		want     float64
+		ok       bool
+	}{
+		"with fees":        {`{"type": "ETH", "data": {"decimals": 18, "quantity": "1000000000000000000", "quantity_with_fees": "1020000000000000000"}}`, true, 1.02, true},
+		"without fees":     {`{"type": "ETH", "data": {"decimals": 18, "quantity": "1000000000000000000", "quantity_with_fees": "1020000000000000000"}}`, false, 1, true},
+		"fees not fetched": {`{"type": "ETH", "data": {"decimals": 18, "quantity": "500000000000000000", "quantity_with_fees": ""}}`, true, 0.5, true},
+		"above int64":      {`{"type": "ETH", "data": {"decimals": 18, "quantity": "25000000000000000000", "quantity_with_fees": "25500000000000000000"}}`, true, 25.5, true},
+		"six decimals":     {`{"type": "ERC20", "data": {"decimals": 6, "symbol": "USDC", "quantity": "12345678", "quantity_with_fees": ""}}`, true, 12.345678, true},
+		"missing decimals": {`{"type": "ETH", "data": {"quantity": "1000000000000000000", "quantity_with_fees": ""}}`, true, 0, false},
+		"invalid quantity": {`{"type": "ETH", "data": {"decimals": 18, "quantity": "1e18", "quantity_with_fees": ""}}`, true, 0, false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := OrderPrice(buyOrder(t, tt.buy), tt.withFees)
+			if ok != tt.ok || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("got %v, %t, want %v, %t", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestGetPriceMissingDecimals(t *testing.T) {
+	order := buyOrder(t, `{"type": "ETH", "data": {"quantity": "1", "quantity_with_fees": "1"}}`)
+	if got := GetPrice(order); got != 0 {
+		t.Errorf("got %v, want 0", got)
+	}
+}
+
+// spotPrices is a price client with fixed prices.
+type spotPrices map[coinbase.SpotPair]float64
+
+func (p spotPrices) RetrieveSpotPrice(crypto coinbase.CryptoSymbol, fiat coinbase.FiatSymbol) float64 {
+	return p[coinbase.SpotPair{Crypto: crypto, Fiat: fiat}]
+}
+
+func (p spotPrices) RetrieveSpotPrices(ctx context.Context, pairs []coinbase.SpotPair) map[coinbase.SpotPair]float64 {
+	results := make(map[coinbase.SpotPair]float64, len(pairs))
+	for _, pair := range pairs {
+		results[pair] = p[pair]
+	}
+
+	return results
+}
+
+func TestCurrencySymbol(t *testing.T) {
+	tests := map[string]coinbase.CryptoSymbol{
+		`{"type": "ETH", "data": {"decimals": 18, "quantity": "1"}}`:                    coinbase.CryptoETH,
+		`{"type": "ERC20", "data": {"decimals": 18, "symbol": "imx", "quantity": "1"}}`: coinbase.CryptoIMX,
+		`{"type": "ERC20", "data": {"decimals": 6, "symbol": "USDC", "quantity": "1"}}`: coinbase.CryptoUSDC,
+		`{"type": "ERC20", "data": {"decimals": 6, "quantity": "1"}}`:                   "ERC20",
+	}
+
+	for buy, want := range tests {
+		if got := CurrencySymbol(buyOrder(t, buy)); got != want {
+			t.Errorf("CurrencySymbol(%s) = %s, want %s", buy, got, want)
+		}
+	}
+}
+
+func TestWriteOrdersMixedCurrencies(t *testing.T) {
+	coinbase.SetPriceClient(spotPrices{
+		{Crypto: coinbase.CryptoETH, Fiat: coinbase.FiatUSD}:  2000,
+		{Crypto: coinbase.CryptoIMX, Fiat: coinbase.FiatUSD}:  0.5,
+		{Crypto: coinbase.CryptoUSDC, Fiat: coinbase.FiatUSD}: 1,
+	})
+	defer coinbase.SetPriceClient(nil)
+
+	list := []api.Order{
+		buyOrder(t, `{"type": "ETH", "data": {"decimals": 18, "quantity": "1000000000000000000", "quantity_with_fees": ""}}`),
+		buyOrder(t, `{"type": "ERC20", "data": {"decimals": 18, "symbol": "IMX", "quantity": "10000000000000000000", "quantity_with_fees": ""}}`),
+		buyOrder(t, `{"type": "ERC20", "data": {"decimals": 6, "symbol": "USDC", "quantity": "40000000", "quantity_with_fees": ""}}`),
+	}
+
+	var buf bytes.Buffer
+	if err := WriteOrders(&buf, list, "standard", coinbase.FiatUSD, "en-US"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"1.000000 ETH / $2,000.00", "10.000000 IMX / $5.00", "40.000000 USDC / $40.00"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("standard output is missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := WriteOrders(&buf, list, "csv=currency,fiat_amount", coinbase.FiatUSD, "en-US"); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "currency,fiat_amount\nETH,2000\nIMX,5\nUSDC,40\n"; buf.String() != want {
+		t.Errorf("got csv\n%s\nwant\n%s", buf.String(), want)
+	}
+}
@@ -2,63 +2,80 @@ package orders
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"math"
 	"net/http"
 	"net/url"
 
+	"github.com/deadloct/immutablex-go-lib/collections"
+	"github.com/deadloct/immutablex-go-lib/metrics"
+	"github.com/deadloct/immutablex-go-lib/utils"
 	"github.com/immutable/imx-core-sdk-golang/imx/api"
 	log "github.com/sirupsen/logrus"
 )
 
 const (
+	GetOrderEndpoint   = "/v3/orders"
 	ListOrdersEndpoint = "/v3/orders"
 )
 
 type RESTClientConfig struct {
 	URL string
+
+	// HTTPClient sends every request, a new http.Client is used when nil.
+	HTTPClient *http.Client
 }
 
 type RESTClient struct {
-	client http.Client
+	client *http.Client
 	url    string
 }
 
 func NewRESTClient(cfg RESTClientConfig) *RESTClient {
-	return &RESTClient{url: cfg.URL}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	return &RESTClient{client: client, url: cfg.URL}
 }
 
 func (c *RESTClient) Start() error { return nil }
 
 func (c *RESTClient) Stop() {}
 
-func (c *RESTClient) GetOrder() (api.Order, error) { return api.Order{}, nil }
+func (c *RESTClient) GetOrder(ctx context.Context, orderID string, includeFees bool) (*api.Order, error) {
+	log.Debugf("fetching order %s (with fees:%t)", orderID, includeFees)
+	url := c.url + GetOrderEndpoint + "/" + orderID
+	if includeFees {
+		url += "?include_fees=true"
+	}
 
-func (c *RESTClient) ListOrders(ctx context.Context, cfg *ListOrdersConfig) ([]api.Order, error) {
-	url := c.getListOrdersURL(cfg)
-	resp, err := c.client.Get(url)
-	if err != nil {
+	var result api.Order
+	if err := utils.GetJSON(ctx, c.client, metrics.EndpointGetOrder, url, &result); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
+	return &result, nil
+}
+
+func (c *RESTClient) ListOrders(ctx context.Context, cfg *ListOrdersConfig) ([]api.Order, error) {
+	url := c.getListOrdersURL(cfg)
 	var parsed api.ListOrdersResponse
-	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
-		log.Errorf("could not parse response from server: %#v", err)
+	if err := utils.GetJSON(ctx, c.client, metrics.EndpointListOrders, url, &parsed); err != nil {
 		return nil, err
 	}
 
+	metrics.GetRecorder().Page(metrics.EndpointListOrders, len(parsed.Result))
+
 	if len(parsed.Result) == 0 {
 		return cfg.Orders, nil
 	}
 
 	cfg.Orders = append(cfg.Orders, parsed.Result...)
-	cfg.Orders = cfg.Orders[:int(math.Min(float64(len(cfg.Orders)), float64(cfg.PageSize)))]
 	cfg.Cursor = parsed.Cursor
 
-	first := *parsed.Result[0].UpdatedTimestamp.Get()
-	last := *parsed.Result[len(parsed.Result)-1].UpdatedTimestamp.Get()
+	first := parsed.Result[0].GetUpdatedTimestamp()
+	last := parsed.Result[len(parsed.Result)-1].GetUpdatedTimestamp()
 	log.Debugf("fetched %v orders from %v to %v", len(parsed.Result), first, last)
 
 	getMore := len(cfg.Orders) < cfg.PageSize || cfg.PageSize == 0
@@ -77,7 +94,7 @@ func (c *RESTClient) getListOrdersURL(cfg *ListOrdersConfig) string {
 	}
 
 	if cfg.AuxiliaryFeeRecipients != "" {
-		v.Set("auxiliary_fee_recipients", cfg.AuxiliaryFeeRecipients)
+		v.Set("auxiliary_fee_recipients", collections.ResolveWallets("auxiliary fee recipients", cfg.AuxiliaryFeeRecipients))
 	}
 
 	if cfg.BuyAssetID != "" {
@@ -97,7 +114,7 @@ func (c *RESTClient) getListOrdersURL(cfg *ListOrdersConfig) string {
 	}
 
 	if cfg.BuyTokenAddress != "" {
-		v.Set("buy_token_address", cfg.BuyTokenAddress)
+		v.Set("buy_token_address", collections.ResolveCollection("buy token address", cfg.BuyTokenAddress))
 	}
 
 	if cfg.BuyTokenID != "" {
@@ -112,6 +129,10 @@ func (c *RESTClient) getListOrdersURL(cfg *ListOrdersConfig) string {
 		v.Set("buy_token_type", cfg.BuyTokenType)
 	}
 
+	if cfg.Cursor != "" {
+		v.Set("cursor", cfg.Cursor)
+	}
+
 	if cfg.Direction != "" {
 		v.Set("direction", cfg.Direction)
 	}
@@ -133,7 +154,7 @@ func (c *RESTClient) getListOrdersURL(cfg *ListOrdersConfig) string {
 	}
 
 	if cfg.PageSize > 0 {
-		v.Set("page_size", fmt.Sprint(cfg.PageSize))
+		v.Set("page_size", fmt.Sprint(cfg.PageSize-len(cfg.Orders)))
 	}
 
 	if cfg.SellAssetID != "" {
@@ -153,7 +174,7 @@ func (c *RESTClient) getListOrdersURL(cfg *ListOrdersConfig) string {
 	}
 
 	if cfg.SellTokenAddress != "" {
-		v.Set("sell_token_address", cfg.SellTokenAddress)
+		v.Set("sell_token_address", collections.ResolveCollection("sell token address", cfg.SellTokenAddress))
 	}
 
 	if cfg.SellTokenID != "" {
@@ -182,7 +203,7 @@ func (c *RESTClient) getListOrdersURL(cfg *ListOrdersConfig) string {
 	}
 
 	if cfg.User != "" {
-		v.Set("user", cfg.User)
+		v.Set("user", collections.ResolveWallet("user", cfg.User))
 	}
 
 	return c.url + ListOrdersEndpoint + "?" + v.Encode()
@@ -0,0 +1,174 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// orderedRow keeps the selected fields in order when encoded.
+type orderedRow struct {
+	fields []string
+	values []interface{}
+}
+
+func (r orderedRow) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range r.fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(f)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := json.Marshal(r.values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func (r orderedRow) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for i, f := range r.fields {
+		var val yaml.Node
+		if err := val.Encode(r.values[i]); err != nil {
+			return nil, err
+		}
+
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: f}, &val)
+	}
+
+	return node, nil
+}
+
+func project(doc *Document, row map[string]interface{}) orderedRow {
+	r := orderedRow{fields: doc.Fields, values: make([]interface{}, len(doc.Fields))}
+	for i, f := range doc.Fields {
+		r.values[i] = Lookup(row, f)
+	}
+
+	return r
+}
+
+// structured returns the values used by the structured formats: the selected
+// fields when the caller chose some, otherwise the full item.
+func structured(doc *Document, raw bool) []interface{} {
+	values := make([]interface{}, len(doc.Rows))
+	for i, row := range doc.Rows {
+		switch {
+		case doc.Selected:
+			values[i] = project(doc, row)
+		case raw:
+			values[i] = doc.Items[i]
+		default:
+			values[i] = row
+		}
+	}
+
+	return values
+}
+
+func formatJSON(w io.Writer, doc *Document) error {
+	values := structured(doc, true)
+
+	var v interface{} = values
+	if doc.Single && len(values) == 1 {
+		v = values[0]
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not convert to json: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+func formatNDJSON(w io.Writer, doc *Document) error {
+	enc := json.NewEncoder(w)
+	for _, v := range structured(doc, true) {
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("could not convert to json: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func formatYAML(w io.Writer, doc *Document) error {
+	values := structured(doc, false)
+
+	var v interface{} = values
+	if doc.Single && len(values) == 1 {
+		v = values[0]
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("could not convert to yaml: %w", err)
+	}
+
+	return enc.Close()
+}
+
+func formatCSV(w io.Writer, doc *Document) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(doc.Fields); err != nil {
+		return err
+	}
+
+	for _, row := range doc.Rows {
+		record := make([]string, len(doc.Fields))
+		for i, f := range doc.Fields {
+			record[i] = Stringify(Lookup(row, f))
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatTable(w io.Writer, doc *Document) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := make([]string, len(doc.Fields))
+	for i, f := range doc.Fields {
+		header[i] = strings.ToUpper(f)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, row := range doc.Rows {
+		cells := make([]string, len(doc.Fields))
+		for i, f := range doc.Fields {
+			// Tabs and newlines would break the alignment.
+			cells[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(Stringify(Lookup(row, f)))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,250 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Document is the data handed to a Formatter: the original items along with a
+// flattened row per item that fields are looked up in.
+type Document struct {
+	// Fields are the columns to output, in order.
+	Fields []string
+	// Selected is true when Fields were chosen by the caller rather than
+	// being the defaults for the item type.
+	Selected bool
+	// Single is true when a single item is printed rather than a list.
+	Single bool
+
+	Items []interface{}
+	Rows  []map[string]interface{}
+}
+
+type Formatter interface {
+	Format(w io.Writer, doc *Document) error
+}
+
+type FormatterFunc func(w io.Writer, doc *Document) error
+
+func (f FormatterFunc) Format(w io.Writer, doc *Document) error {
+	return f(w, doc)
+}
+
+// Factory creates a formatter from the argument of an output spec (the text
+// after "="), returning the fields it selects, if any.
+type Factory func(arg string) (Formatter, []string, error)
+
+var (
+	factories = map[string]Factory{
+		"csv":           fieldsFactory(FormatterFunc(formatCSV)),
+		"json":          fieldsFactory(FormatterFunc(formatJSON)),
+		"ndjson":        fieldsFactory(FormatterFunc(formatNDJSON)),
+		"table":         fieldsFactory(FormatterFunc(formatTable)),
+		"yaml":          fieldsFactory(FormatterFunc(formatYAML)),
+		"template":      newTemplateFactory,
+		"template-file": newTemplateFileFactory,
+	}
+	muFactories sync.RWMutex
+)
+
+func fieldsFactory(f Formatter) Factory {
+	return func(arg string) (Formatter, []string, error) {
+		return f, ParseFields(arg), nil
+	}
+}
+
+// Register adds a formatter under name whose spec argument is a list of fields,
+// replacing any existing formatter with the same name.
+func Register(name string, f Formatter) {
+	RegisterFactory(name, fieldsFactory(f))
+}
+
+// RegisterFactory adds a formatter under name that interprets the spec
+// argument itself, replacing any existing formatter with the same name.
+func RegisterFactory(name string, f Factory) {
+	muFactories.Lock()
+	defer muFactories.Unlock()
+
+	factories[strings.ToLower(name)] = f
+}
+
+// New returns the formatter for an output spec such as "table" or
+// "csv=name,token_id", along with the fields it selects.
+func New(spec string) (Formatter, []string, error) {
+	name, arg := ParseSpec(spec)
+
+	muFactories.RLock()
+	f, ok := factories[name]
+	muFactories.RUnlock()
+
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown output format %q (available: %s)", name, strings.Join(Names(), ", "))
+	}
+
+	return f(arg)
+}
+
+// Names returns the names of all registered formatters.
+func Names() []string {
+	muFactories.RLock()
+	defer muFactories.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// ParseSpec splits an output spec such as "csv=name,token_id" into the format
+// name and its argument. For most formats the argument is a comma separated
+// list of fields.
+func ParseSpec(spec string) (string, string) {
+	name, arg := spec, ""
+	if i := strings.IndexByte(spec, '='); i >= 0 {
+		name, arg = spec[:i], spec[i+1:]
+	}
+
+	return strings.ToLower(strings.TrimSpace(name)), arg
+}
+
+// IsStandard reports whether spec selects the plain text output each package
+// writes itself rather than a registered formatter: an empty spec, "standard"
+// or "text". Like before formatters could be registered, unknown formats such
+// as "normal" fall back to standard output with a warning; New returns an
+// error for them instead.
+func IsStandard(spec string) bool {
+	name, _ := ParseSpec(spec)
+	switch name {
+	case "", "standard", "text":
+		return true
+	}
+
+	muFactories.RLock()
+	_, ok := factories[name]
+	muFactories.RUnlock()
+
+	if !ok {
+		log.Warnf("unknown output format %q, using standard output (available: %s)", name, strings.Join(Names(), ", "))
+	}
+
+	return !ok
+}
+
+// ParseFields splits a comma separated field list.
+func ParseFields(arg string) []string {
+	var fields []string
+	for _, f := range strings.Split(arg, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+// NewDocument builds a document from items. Each row holds the item's JSON
+// fields merged with the values returned by extra, which may be nil. When
+// fields is empty, defaultFields are used.
+func NewDocument(items []interface{}, fields, defaultFields []string, extra func(i int) map[string]interface{}) (*Document, error) {
+	doc := &Document{
+		Fields:   fields,
+		Selected: len(fields) > 0,
+		Items:    items,
+		Rows:     make([]map[string]interface{}, 0, len(items)),
+	}
+
+	if !doc.Selected {
+		doc.Fields = defaultFields
+	}
+
+	for i, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert item to json: %w", err)
+		}
+
+		row := make(map[string]interface{})
+		if err := json.Unmarshal(data, &row); err != nil {
+			return nil, fmt.Errorf("could not convert item to row: %w", err)
+		}
+
+		if extra != nil {
+			for k, v := range extra(i) {
+				row[k] = v
+			}
+		}
+
+		doc.Rows = append(doc.Rows, row)
+	}
+
+	return doc, nil
+}
+
+// Write builds a document from items and writes it with the formatter for
+// spec, see New and NewDocument.
+func Write(w io.Writer, spec string, single bool, items []interface{}, defaultFields []string, extra func(i int) map[string]interface{}) error {
+	f, fields, err := New(spec)
+	if err != nil {
+		return err
+	}
+
+	doc, err := NewDocument(items, fields, defaultFields, extra)
+	if err != nil {
+		return err
+	}
+
+	doc.Single = single
+	return f.Format(w, doc)
+}
+
+// Lookup returns the value of a field in a row. Nested values are addressed
+// with dots, e.g. "collection.name" or "metadata.rarity".
+func Lookup(row map[string]interface{}, field string) interface{} {
+	if v, ok := row[field]; ok {
+		return v
+	}
+
+	var cur interface{} = row
+	for _, part := range strings.Split(field, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		cur = m[part]
+	}
+
+	return cur
+}
+
+// Stringify converts a row value to text for tabular formats.
+func Stringify(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case fmt.Stringer:
+		return val.String()
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(val)
+	}
+}
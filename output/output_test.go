@@ -0,0 +1,162 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIsStandard(t *testing.T) {
+	for spec, want := range map[string]bool{
+		"":               true,
+		"standard":       true,
+		" Text ":         true,
+		"text=name":      true,
+		"normal":         true,
+		"json":           false,
+		"csv=name,token": false,
+		"template={{.}}": false,
+	} {
+		if got := IsStandard(spec); got != want {
+			t.Errorf("IsStandard(%q) = %t, want %t", spec, got, want)
+		}
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		spec, name, arg string
+	}{
+		{"table", "table", ""},
+		{" CSV =name, token_id", "csv", "name, token_id"},
+		{"template={{.a}}={{.b}}", "template", "{{.a}}={{.b}}"},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		if name, arg := ParseSpec(tt.spec); name != tt.name || arg != tt.arg {
+			t.Errorf("ParseSpec(%q) = %q, %q, want %q, %q", tt.spec, name, arg, tt.name, tt.arg)
+		}
+	}
+
+	if got := ParseFields(" name,,token_id , "); len(got) != 2 || got[0] != "name" || got[1] != "token_id" {
+		t.Errorf("got fields %q", got)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, fields, err := New("csv=name,token_id"); err != nil || len(fields) != 2 {
+		t.Errorf("got fields %q and error %v", fields, err)
+	}
+
+	if _, _, err := New("normal"); err == nil {
+		t.Error("got no error for an unknown format")
+	}
+
+	if _, _, err := New("template={{.name"); err == nil {
+		t.Error("got no error for an invalid template")
+	}
+}
+
+type item struct {
+	Name       string            `json:"name"`
+	TokenID    string            `json:"token_id"`
+	Collection map[string]string `json:"collection"`
+}
+
+var items = []interface{}{
+	item{Name: "Hero\t1", TokenID: "1", Collection: map[string]string{"name": "Heroes"}},
+	item{Name: "Hero, the second", TokenID: "2"},
+}
+
+func TestFormatters(t *testing.T) {
+	tests := []struct {
+		spec   string
+		single bool
+		want   string
+	}{
+		{
+			spec: "json=name,collection.name",
+			want: `[
+  {
+    "name": "Hero\t1",
+    "collection.name": "Heroes"
+  },
+  {
+    "name": "Hero, the second",
+    "collection.name": null
+  }
+]
+`,
+		},
+		{
+			spec:   "json=token_id",
+			single: true,
+			want: `{
+  "token_id": "1"
+}
+`,
+		},
+		{
+			spec: "ndjson=token_id,extra",
+			want: `{"token_id":"1","extra":"a"}
+{"token_id":"2","extra":"b"}
+`,
+		},
+		{
+			spec: "csv",
+			want: "name,token_id\nHero\t1,1\n\"Hero, the second\",2\n",
+		},
+		{
+			spec: "table=token_id,name,collection",
+			want: "TOKEN_ID  NAME              COLLECTION\n" +
+				"1         Hero 1            {\"name\":\"Heroes\"}\n" +
+				"2         Hero, the second  \n",
+		},
+		{
+			spec: "yaml=token_id,collection.name",
+			want: "- token_id: \"1\"\n  collection.name: Heroes\n- token_id: \"2\"\n  collection.name: null\n",
+		},
+		{
+			spec: "template={{.token_id}}: {{upper .name}}",
+			want: "1: HERO\t1\n2: HERO, THE SECOND\n",
+		},
+	}
+
+	extra := func(i int) map[string]interface{} {
+		return map[string]interface{}{"extra": string(rune('a' + i))}
+	}
+
+	for _, tt := range tests {
+		values := items
+		if tt.single {
+			values = items[:1]
+		}
+
+		var buf bytes.Buffer
+		if err := Write(&buf, tt.spec, tt.single, values, []string{"name", "token_id"}, extra); err != nil {
+			t.Errorf("%s: %v", tt.spec, err)
+			continue
+		}
+
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestJSONWithoutFieldsKeepsItems(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, "json", true, items[1:], []string{"name"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+  "name": "Hero, the second",
+  "token_id": "2",
+  "collection": null
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
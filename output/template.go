@@ -0,0 +1,227 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+	"github.com/deadloct/immutablex-go-lib/utils"
+)
+
+// TemplateFormatter executes a text/template once per item. The template's dot
+// is the item's row, so JSON field names and computed fields are available,
+// e.g. {{.name}} or {{.immutascan_url}}.
+type TemplateFormatter struct {
+	tmpl *template.Template
+}
+
+func NewTemplateFormatter(text string) (*TemplateFormatter, error) {
+	tmpl, err := template.New("output").Funcs(FuncMap()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse output template: %w", err)
+	}
+
+	return &TemplateFormatter{tmpl: tmpl}, nil
+}
+
+func (f *TemplateFormatter) Format(w io.Writer, doc *Document) error {
+	for _, row := range doc.Rows {
+		var buf bytes.Buffer
+		if err := f.tmpl.Execute(&buf, row); err != nil {
+			return fmt.Errorf("could not execute output template: %w", err)
+		}
+
+		if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newTemplateFactory(arg string) (Formatter, []string, error) {
+	f, err := NewTemplateFormatter(arg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, nil, nil
+}
+
+func newTemplateFileFactory(arg string) (Formatter, []string, error) {
+	content, err := os.ReadFile(arg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read output template %s: %w", arg, err)
+	}
+
+	return newTemplateFactory(string(content))
+}
+
+// FuncMap returns the helper functions available to output templates:
+//
+//	immutascan "address" addr [tokenID]  link to a collection or asset
+//	immutascan "order" id                link to an order
+//	price amount symbol                  "0.05 ETH"
+//	fiat amount crypto [fiat]            converts a crypto amount to fiat
+//	formatFiat amount [fiat] [locale]    "$1,234.56"
+//	truncate addr                        "0x6465…1d95"
+//	humanize timestamp                   "3 hours ago"
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"immutascan": Immutascan,
+		"price":      FormatPrice,
+		"fiat":       ToFiat,
+		"formatFiat": formatFiat,
+		"truncate":   TruncateAddress,
+		"humanize":   Humanize,
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"join":       strings.Join,
+	}
+}
+
+// Immutascan returns the Immutascan URL for the given path segments, e.g.
+// ("address", collectionAddr, tokenID) or ("order", orderID).
+func Immutascan(kind string, parts ...interface{}) string {
+	segments := []string{utils.ImmutascanURL, kind}
+	for _, p := range parts {
+		segments = append(segments, Stringify(p))
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// FormatPrice formats a crypto amount without trailing zeros, e.g. "0.05 ETH".
+func FormatPrice(amount interface{}, symbol string) string {
+	v, err := toFloat(amount)
+	if err != nil {
+		return Stringify(amount) + " " + symbol
+	}
+
+	return strings.TrimSpace(strconv.FormatFloat(v, 'f', -1, 64) + " " + symbol)
+}
+
+// ToFiat converts a crypto amount to fiat using the current spot price. The
+// fiat currency defaults to USD.
+func ToFiat(amount interface{}, crypto string, fiat ...string) (float64, error) {
+	v, err := toFloat(amount)
+	if err != nil {
+		return 0, err
+	}
+
+	symbol := coinbase.FiatUSD
+	if len(fiat) > 0 && fiat[0] != "" {
+		if symbol, err = coinbase.NewFiatSymbol(fiat[0]); err != nil {
+			return 0, err
+		}
+	}
+
+	spot := coinbase.GetPriceClient().RetrieveSpotPrice(coinbase.CryptoSymbol(crypto), symbol)
+	return v * spot, nil
+}
+
+func formatFiat(amount interface{}, args ...string) (string, error) {
+	v, err := toFloat(amount)
+	if err != nil {
+		return "", err
+	}
+
+	fiat, locale := coinbase.FiatUSD, coinbase.DefaultLocale
+	if len(args) > 0 && args[0] != "" {
+		if fiat, err = coinbase.NewFiatSymbol(args[0]); err != nil {
+			return "", err
+		}
+	}
+
+	if len(args) > 1 {
+		locale = args[1]
+	}
+
+	return coinbase.FormatFiat(v, fiat, locale), nil
+}
+
+// TruncateAddress shortens an address to its first and last four characters
+// after the 0x prefix, e.g. "0x6465…1d95".
+func TruncateAddress(addr string) string {
+	if len(addr) <= 12 {
+		return addr
+	}
+
+	return addr[:6] + "…" + addr[len(addr)-4:]
+}
+
+// Humanize describes how long ago an RFC 3339 timestamp was, e.g. "3 hours
+// ago". Values that can't be parsed are returned unchanged.
+func Humanize(ts interface{}) string {
+	var t time.Time
+	switch v := ts.(type) {
+	case time.Time:
+		t = v
+	case string:
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return v
+		}
+		t = parsed
+	default:
+		return Stringify(ts)
+	}
+
+	d := time.Since(t)
+	suffix := "ago"
+	if d < 0 {
+		d, suffix = -d, "from now"
+	}
+
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d/time.Minute), "minute") + " " + suffix
+	case d < 24*time.Hour:
+		return plural(int(d/time.Hour), "hour") + " " + suffix
+	case d < 30*24*time.Hour:
+		return plural(int(d/(24*time.Hour)), "day") + " " + suffix
+	case d < 365*24*time.Hour:
+		return plural(int(d/(30*24*time.Hour)), "month") + " " + suffix
+	default:
+		return plural(int(d/(365*24*time.Hour)), "year") + " " + suffix
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+func toFloat(v interface{}) (float64, error) {
+	switch val := v.(type) {
+	case float64:
+		return val, nil
+	case float32:
+		return float64(val), nil
+	case int:
+		return float64(val), nil
+	case int32:
+		return float64(val), nil
+	case int64:
+		return float64(val), nil
+	case string:
+		return strconv.ParseFloat(val, 64)
+	default:
+		return 0, fmt.Errorf("cannot convert %T to a number", v)
+	}
+}
@@ -0,0 +1,179 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/coinbase"
+)
+
+// spotPrices is a price client with fixed prices.
+type spotPrices map[coinbase.SpotPair]float64
+
+func (p spotPrices) RetrieveSpotPrice(crypto coinbase.CryptoSymbol, fiat coinbase.FiatSymbol) float64 {
+	return p[coinbase.SpotPair{Crypto: crypto, Fiat: fiat}]
+}
+
+func (p spotPrices) RetrieveSpotPrices(ctx context.Context, pairs []coinbase.SpotPair) map[coinbase.SpotPair]float64 {
+	results := make(map[coinbase.SpotPair]float64, len(pairs))
+	for _, pair := range pairs {
+		results[pair] = p[pair]
+	}
+
+	return results
+}
+
+func TestImmutascan(t *testing.T) {
+	tests := []struct {
+		kind  string
+		parts []interface{}
+		want  string
+	}{
+		{"address", []interface{}{"0xabc"}, "https://immutascan.io/address/0xabc"},
+		{"address", []interface{}{"0xabc", "7"}, "https://immutascan.io/address/0xabc/7"},
+		{"order", []interface{}{float64(1234)}, "https://immutascan.io/order/1234"},
+	}
+
+	for _, tt := range tests {
+		if got := Immutascan(tt.kind, tt.parts...); got != tt.want {
+			t.Errorf("Immutascan(%s, %v) = %s, want %s", tt.kind, tt.parts, got, tt.want)
+		}
+	}
+}
+
+func TestFormatPrice(t *testing.T) {
+	tests := []struct {
+		amount interface{}
+		symbol string
+		want   string
+	}{
+		{0.05, "ETH", "0.05 ETH"},
+		{float64(2), "IMX", "2 IMX"},
+		{"1.500", "USDC", "1.5 USDC"},
+		{3, "", "3"},
+		// Amounts that aren't numbers are printed as they are.
+		{"n/a", "ETH", "n/a ETH"},
+		{nil, "ETH", " ETH"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatPrice(tt.amount, tt.symbol); got != tt.want {
+			t.Errorf("FormatPrice(%v, %s) = %q, want %q", tt.amount, tt.symbol, got, tt.want)
+		}
+	}
+}
+
+func TestToFiat(t *testing.T) {
+	coinbase.SetPriceClient(spotPrices{
+		{Crypto: coinbase.CryptoETH, Fiat: coinbase.FiatUSD}: 2000,
+		{Crypto: coinbase.CryptoETH, Fiat: coinbase.FiatEUR}: 1800,
+	})
+	defer coinbase.SetPriceClient(nil)
+
+	tests := []struct {
+		amount  interface{}
+		fiat    []string
+		want    float64
+		wantErr bool
+	}{
+		{0.5, nil, 1000, false},
+		{"0.5", []string{"eur"}, 900, false},
+		{0.5, []string{""}, 1000, false},
+		{0.5, []string{"euro"}, 0, true},
+		{"lots", nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ToFiat(tt.amount, "ETH", tt.fiat...)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ToFiat(%v, ETH, %v) = %v, %v, want %v", tt.amount, tt.fiat, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormatFiat(t *testing.T) {
+	tests := []struct {
+		amount  interface{}
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{1234.5, nil, "$1,234.50", false},
+		{"1234.5", []string{"eur", "de-DE"}, "1.234,50 €", false},
+		{1234.5, []string{"usd1"}, "", true},
+		{"lots", nil, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := formatFiat(tt.amount, tt.args...)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("formatFiat(%v, %v) = %q, %v, want %q", tt.amount, tt.args, got, err, tt.want)
+		}
+	}
+}
+
+func TestTruncateAddress(t *testing.T) {
+	tests := map[string]string{
+		"0x6465ef3009f3c474774f4afb607a5d600ea71d95": "0x6465…1d95",
+		"0x12345678ab": "0x12345678ab",
+		"":             "",
+	}
+
+	for addr, want := range tests {
+		if got := TruncateAddress(addr); got != want {
+			t.Errorf("TruncateAddress(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
+
+func TestHumanize(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		ts   interface{}
+		want string
+	}{
+		{now.Add(-10 * time.Second), "just now"},
+		{now.Add(-90 * time.Second), "1 minute ago"},
+		{now.Add(-3*time.Hour - time.Minute).Format(time.RFC3339), "3 hours ago"},
+		{now.Add(-25 * time.Hour), "1 day ago"},
+		{now.Add(-65 * 24 * time.Hour), "2 months ago"},
+		{now.Add(-400 * 24 * time.Hour), "1 year ago"},
+		{now.Add(2*time.Hour + time.Minute), "2 hours from now"},
+		{now.Add(24*time.Hour + time.Minute), "1 day from now"},
+		{"yesterday", "yesterday"},
+		{float64(5), "5"},
+	}
+
+	for _, tt := range tests {
+		if got := Humanize(tt.ts); got != tt.want {
+			t.Errorf("Humanize(%v) = %q, want %q", tt.ts, got, tt.want)
+		}
+	}
+}
+
+func TestFuncMapInTemplate(t *testing.T) {
+	f, err := NewTemplateFormatter(`{{truncate .user}} {{price .amount "ETH"}} {{formatFiat .amount "gbp"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	doc := &Document{Rows: []map[string]interface{}{
+		{"user": "0x6465ef3009f3c474774f4afb607a5d600ea71d95", "amount": 0.25},
+	}}
+	if err := f.Format(&buf, doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := buf.String(), "0x6465…1d95 0.25 ETH £0.25\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	doc.Rows[0]["amount"] = "lots"
+	if err := f.Format(&buf, doc); err == nil {
+		t.Error("got no error for an amount that isn't a number")
+	}
+}
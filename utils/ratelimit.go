@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	DefaultRequestsPerSecond = 5
+	DefaultRequestBurst      = 10
+)
+
+// RateLimiter is shared by everything in the process that forwards requests to
+// the Immutable X API, so they stay within the API's limits as a whole.
+var RateLimiter = rate.NewLimiter(DefaultRequestsPerSecond, DefaultRequestBurst)
+
+// RateLimitedTransport waits for Limiter before sending each request, so a
+// client listing every page of a collection is limited page by page.
+type RateLimitedTransport struct {
+	// Limiter defaults to RateLimiter.
+	Limiter *rate.Limiter
+	// Transport defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+func (t *RateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	limiter := t.Limiter
+	if limiter == nil {
+		limiter = RateLimiter
+	}
+
+	if err := limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return transport.RoundTrip(req)
+}
+
+// NewRateLimitedClient returns an http.Client whose requests all wait for
+// RateLimiter.
+func NewRateLimitedClient() *http.Client {
+	return &http.Client{Transport: &RateLimitedTransport{}}
+}
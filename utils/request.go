@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/metrics"
+	log "github.com/sirupsen/logrus"
+)
+
+// Get sends a GET request for url that is cancelled along with ctx.
+func Get(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Do(req)
+}
+
+// GetJSON fetches url and decodes the response into v. The request is
+// recorded as a request to endpoint once its body has been read, and ctx is
+// tagged with endpoint so a RetryTransport records its retries.
+func GetJSON(ctx context.Context, client *http.Client, endpoint, url string, v interface{}) error {
+	start := time.Now()
+	resp, err := Get(metrics.WithEndpoint(ctx, endpoint), client, url)
+	if err != nil {
+		metrics.ObserveResponse(endpoint, start, nil)
+		return err
+	}
+	defer metrics.ObserveResponse(endpoint, start, resp)
+	defer resp.Body.Close()
+
+	if err := CheckResponse(resp); err != nil {
+		return err
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		log.Errorf("could not parse response from server: %#v", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// APIError is a non 200 response from the Immutable X API.
+type APIError struct {
+	StatusCode int
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("api error %d %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+
+	return fmt.Sprintf("api error %d", e.StatusCode)
+}
+
+// CheckResponse returns an *APIError for non 200 responses, decoding the
+// API's {"code", "message"} body when present.
+func CheckResponse(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err := json.Unmarshal(body, apiErr); err != nil {
+		apiErr.Message = string(body)
+	}
+
+	return apiErr
+}
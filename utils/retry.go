@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/metrics"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultMaxRetries = 3
+	DefaultRetryDelay = 500 * time.Millisecond
+)
+
+// RetryTransport retries GET requests the API answers with 429, 502, 503 or
+// 504, waiting for the Retry-After header in seconds or else an exponential
+// backoff. Each retry is recorded for the endpoint the request's context was
+// tagged with by metrics.WithEndpoint. Clients don't retry unless given one,
+// e.g. to retry requests that each wait for the rate limiter:
+//
+//	client := &http.Client{Transport: &utils.RetryTransport{Transport: &utils.RateLimitedTransport{}}}
+//	orders.NewClient(orders.NewClientConfigWithHTTPClient(alchemyKey, client))
+type RetryTransport struct {
+	// MaxRetries defaults to DefaultMaxRetries.
+	MaxRetries int
+	// Delay is the wait before the first retry, doubled for each one after.
+	// Defaults to DefaultRetryDelay.
+	Delay time.Duration
+	// Transport defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	maxRetries := t.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = DefaultMaxRetries
+	}
+
+	delay := t.Delay
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		resp, err := transport.RoundTrip(req)
+		if err != nil || attempt == maxRetries || req.Method != http.MethodGet || !retryable(resp.StatusCode) {
+			return resp, err
+		}
+
+		wait := delay << attempt
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		log.Debugf("retrying %s in %v after status %d", req.URL, wait, resp.StatusCode)
+		metrics.GetRecorder().Retry(metrics.EndpointFrom(ctx))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewRetryClient returns an http.Client that retries failed API requests, see
+// RetryTransport.
+func NewRetryClient() *http.Client {
+	return &http.Client{Transport: &RetryTransport{}}
+}
@@ -0,0 +1,287 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/metrics"
+)
+
+// testRecorder records retries and requests.
+type testRecorder struct {
+	metrics.NoopRecorder
+
+	retries  []string
+	requests []int
+	duration time.Duration
+	sync.Mutex
+}
+
+func (r *testRecorder) Retry(endpoint string) {
+	r.Lock()
+	defer r.Unlock()
+	r.retries = append(r.retries, endpoint)
+}
+
+func (r *testRecorder) Request(endpoint, backend string, status int, duration time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+	r.requests = append(r.requests, status)
+	r.duration = duration
+}
+
+func useRecorder(t *testing.T) *testRecorder {
+	rec := &testRecorder{}
+	metrics.SetRecorder(rec)
+	t.Cleanup(func() { metrics.SetRecorder(nil) })
+	return rec
+}
+
+// failingServer answers the first failures requests with status.
+func failingServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &calls
+}
+
+func retryClient() *http.Client {
+	return &http.Client{Transport: &RetryTransport{Delay: time.Millisecond}}
+}
+
+func TestRetryTransportRetries(t *testing.T) {
+	rec := useRecorder(t)
+	srv, calls := failingServer(t, 2, http.StatusServiceUnavailable, nil)
+
+	var result struct{ OK bool }
+	if err := GetJSON(context.Background(), retryClient(), metrics.EndpointListOrders, srv.URL, &result); err != nil || !result.OK {
+		t.Fatalf("got %+v, %v", result, err)
+	}
+
+	if *calls != 3 {
+		t.Errorf("got %d requests, want 3", *calls)
+	}
+
+	if len(rec.retries) != 2 || rec.retries[0] != metrics.EndpointListOrders {
+		t.Errorf("recorded retries %v", rec.retries)
+	}
+
+	if len(rec.requests) != 1 || rec.requests[0] != http.StatusOK {
+		t.Errorf("recorded requests %v, want one 200", rec.requests)
+	}
+}
+
+func TestRetryTransportGivesUp(t *testing.T) {
+	rec := useRecorder(t)
+	srv, calls := failingServer(t, 100, http.StatusTooManyRequests, http.Header{"Retry-After": {"0"}})
+
+	err := GetJSON(context.Background(), retryClient(), metrics.EndpointGetAsset, srv.URL, &struct{}{})
+	if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("got %v, want a 429 APIError", err)
+	}
+
+	if *calls != DefaultMaxRetries+1 || len(rec.retries) != DefaultMaxRetries {
+		t.Errorf("got %d requests and %d retries", *calls, len(rec.retries))
+	}
+}
+
+func TestRetryTransportSkipsOtherErrors(t *testing.T) {
+	rec := useRecorder(t)
+	srv, calls := failingServer(t, 1, http.StatusNotFound, nil)
+
+	if err := GetJSON(context.Background(), retryClient(), metrics.EndpointGetOrder, srv.URL, &struct{}{}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if *calls != 1 || len(rec.retries) != 0 {
+		t.Errorf("got %d requests and %d retries, want no retries", *calls, len(rec.retries))
+	}
+
+	if len(rec.requests) != 1 || rec.requests[0] != http.StatusNotFound {
+		t.Errorf("recorded requests %v, want one 404", rec.requests)
+	}
+}
+
+func TestRetryTransportStopsWithContext(t *testing.T) {
+	srv, _ := failingServer(t, 100, http.StatusServiceUnavailable, nil)
+	client := &http.Client{Transport: &RetryTransport{Delay: time.Hour}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := GetJSON(ctx, client, metrics.EndpointGetOrder, srv.URL, &struct{}{}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+// timedServer answers every request with status and records when each
+// arrived.
+func timedServer(t *testing.T, status int, header http.Header) (*httptest.Server, func() []time.Time) {
+	var (
+		times []time.Time
+		mu    sync.Mutex
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]time.Time(nil), times...)
+	}
+}
+
+func TestRetryTransportBacksOff(t *testing.T) {
+	srv, times := timedServer(t, http.StatusServiceUnavailable, nil)
+	client := &http.Client{Transport: &RetryTransport{MaxRetries: 3, Delay: 20 * time.Millisecond}}
+
+	GetJSON(context.Background(), client, metrics.EndpointListAssets, srv.URL, &struct{}{})
+
+	got := times()
+	if len(got) != 4 {
+		t.Fatalf("got %d requests, want 4", len(got))
+	}
+
+	// The delay doubles after each retry.
+	for i, want := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 80 * time.Millisecond} {
+		if gap := got[i+1].Sub(got[i]); gap < want {
+			t.Errorf("retry %d after %v, want at least %v", i+1, gap, want)
+		}
+	}
+}
+
+func TestRetryTransportRetryAfter(t *testing.T) {
+	tests := []struct {
+		retryAfter string
+		delay      time.Duration
+		min, max   time.Duration
+	}{
+		// Retry-After replaces the backoff, whether it's shorter or longer.
+		{"0", time.Hour, 0, 500 * time.Millisecond},
+		{"1", time.Millisecond, time.Second, 2 * time.Second},
+		// Dates and invalid values fall back to the backoff.
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 30 * time.Millisecond, 30 * time.Millisecond, 500 * time.Millisecond},
+		{"soon", 30 * time.Millisecond, 30 * time.Millisecond, 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		srv, times := timedServer(t, http.StatusTooManyRequests, http.Header{"Retry-After": {tt.retryAfter}})
+		client := &http.Client{Transport: &RetryTransport{MaxRetries: 1, Delay: tt.delay}}
+
+		GetJSON(context.Background(), client, metrics.EndpointListOrders, srv.URL, &struct{}{})
+
+		got := times()
+		if len(got) != 2 {
+			t.Errorf("Retry-After %s: got %d requests, want 2", tt.retryAfter, len(got))
+			continue
+		}
+
+		if gap := got[1].Sub(got[0]); gap < tt.min || gap > tt.max {
+			t.Errorf("Retry-After %s: retried after %v, want between %v and %v", tt.retryAfter, gap, tt.min, tt.max)
+		}
+	}
+}
+
+type failingTransport struct {
+	calls int32
+}
+
+func (t *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.calls, 1)
+	return nil, errors.New("connection reset")
+}
+
+func TestRetryTransportConditions(t *testing.T) {
+	tests := []struct {
+		method string
+		status int
+		want   int32
+	}{
+		{http.MethodGet, http.StatusTooManyRequests, 2},
+		{http.MethodGet, http.StatusBadGateway, 2},
+		{http.MethodGet, http.StatusServiceUnavailable, 2},
+		{http.MethodGet, http.StatusGatewayTimeout, 2},
+		{http.MethodGet, http.StatusInternalServerError, 1},
+		{http.MethodGet, http.StatusBadRequest, 1},
+		{http.MethodGet, http.StatusOK, 1},
+		// Only GETs are safe to send again.
+		{http.MethodPost, http.StatusServiceUnavailable, 1},
+	}
+
+	for _, tt := range tests {
+		srv, calls := failingServer(t, 1, tt.status, nil)
+		client := &http.Client{Transport: &RetryTransport{MaxRetries: 1, Delay: time.Millisecond}}
+
+		req, err := http.NewRequest(tt.method, srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s %d: %v", tt.method, tt.status, err)
+		}
+		resp.Body.Close()
+
+		if got := atomic.LoadInt32(calls); got != tt.want {
+			t.Errorf("%s %d: got %d requests, want %d", tt.method, tt.status, got, tt.want)
+		}
+	}
+
+	// Requests that fail without a response aren't retried.
+	transport := &failingTransport{}
+	client := &http.Client{Transport: &RetryTransport{Delay: time.Millisecond, Transport: transport}}
+	if _, err := client.Get("http://example.invalid"); err == nil {
+		t.Error("expected an error")
+	}
+
+	if transport.calls != 1 {
+		t.Errorf("got %d requests after a transport error, want 1", transport.calls)
+	}
+}
+
+func TestGetJSONMeasuresBody(t *testing.T) {
+	rec := useRecorder(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"a": `))
+		w.(http.Flusher).Flush()
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`1}`))
+	}))
+	defer srv.Close()
+
+	var result struct{ A int }
+	if err := GetJSON(context.Background(), srv.Client(), metrics.EndpointGetOrder, srv.URL, &result); err != nil || result.A != 1 {
+		t.Fatalf("got %+v, %v", result, err)
+	}
+
+	if rec.duration < 50*time.Millisecond {
+		t.Errorf("recorded %v, before the body was read", rec.duration)
+	}
+}
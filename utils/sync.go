@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"context"
+	"time"
+)
+
+// SyncCheckpoint is how far an incremental sync of results ordered by
+// updated_at has got. It's stored alongside the synced data.
+type SyncCheckpoint struct {
+	// UpdatedAt is the newest updated_at timestamp stored.
+	UpdatedAt string `json:"updated_at"`
+	// QueryFrom and Cursor are set while a sync is in progress, so an
+	// interrupted sync resumes with the same query instead of starting over.
+	QueryFrom string    `json:"query_from,omitempty"`
+	Cursor    string    `json:"cursor,omitempty"`
+	LastSync  time.Time `json:"last_sync"`
+}
+
+// SyncPages fetches the results updated since cp.UpdatedAt, oldest first, and
+// stores each page along with the checkpoint after it, so a sync that fails
+// part way continues from the last stored page on the next call. fetch gets
+// the page at cursor of the query from queryFrom and returns the next cursor.
+// store must save the page and cp, which is already moved past it, in one
+// transaction and advance cp.UpdatedAt. It returns the number of pages
+// stored.
+//
+// Results updated at the watermark timestamp are fetched again, since
+// updated_at isn't unique, so storing a result must be idempotent.
+func SyncPages[T any](ctx context.Context, cp *SyncCheckpoint, pageSize int,
+	fetch func(ctx context.Context, queryFrom, cursor string) ([]T, string, error),
+	store func(page []T, now time.Time) error) (int, error) {
+	if cp.Cursor == "" {
+		cp.QueryFrom = cp.UpdatedAt
+	}
+
+	pages := 0
+	for {
+		page, cursor, err := fetch(ctx, cp.QueryFrom, cp.Cursor)
+		if err != nil {
+			return pages, err
+		}
+
+		now := time.Now().UTC()
+		done := len(page) < pageSize || cursor == ""
+		cp.Cursor = cursor
+		if done {
+			cp.Cursor, cp.QueryFrom = "", ""
+			cp.LastSync = now
+		}
+
+		if err := store(page, now); err != nil {
+			return pages, err
+		}
+
+		pages++
+		if done {
+			return pages, nil
+		}
+	}
+}
+
+// LaterTimestamp reports whether RFC 3339 timestamp a is after b. Unparseable
+// values fall back to string comparison.
+func LaterTimestamp(a, b string) bool {
+	if b == "" {
+		return a != ""
+	}
+
+	ta, errA := time.Parse(time.RFC3339Nano, a)
+	tb, errB := time.Parse(time.RFC3339Nano, b)
+	if errA != nil || errB != nil {
+		return a > b
+	}
+
+	return ta.After(tb)
+}
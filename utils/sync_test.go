@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSyncPagesResumes(t *testing.T) {
+	ctx := context.Background()
+	// Five results over pages of two, the third page fails once.
+	results := []string{"a", "b", "c", "d", "e"}
+	failed := false
+	fetch := func(ctx context.Context, queryFrom, cursor string) ([]string, string, error) {
+		offset := 0
+		if cursor != "" {
+			fmt.Sscan(cursor, &offset)
+		}
+
+		if offset == 4 && !failed {
+			failed = true
+			return nil, "", errors.New("unavailable")
+		}
+
+		end := offset + 2
+		if end > len(results) {
+			end = len(results)
+		}
+
+		return results[offset:end], fmt.Sprint(end), nil
+	}
+
+	var stored []string
+	var cp SyncCheckpoint
+	store := func(page []string, now time.Time) error {
+		stored = append(stored, page...)
+		cp.UpdatedAt = "2022-01-01T00:00:00Z"
+		return nil
+	}
+
+	pages, err := SyncPages(ctx, &cp, 2, fetch, store)
+	if err == nil || pages != 2 {
+		t.Fatalf("got %d pages, %v, want 2 pages and an error", pages, err)
+	}
+
+	if cp.Cursor != "4" || !cp.LastSync.IsZero() {
+		t.Errorf("checkpoint after failure %+v, want cursor 4", cp)
+	}
+
+	pages, err = SyncPages(ctx, &cp, 2, fetch, store)
+	if err != nil || pages != 1 {
+		t.Fatalf("got %d pages, %v, want 1 page", pages, err)
+	}
+
+	if len(stored) != len(results) {
+		t.Errorf("stored %v, want %v", stored, results)
+	}
+
+	if cp.Cursor != "" || cp.QueryFrom != "" || cp.LastSync.IsZero() {
+		t.Errorf("checkpoint after sync %+v, want no cursor and a last sync time", cp)
+	}
+}
+
+func TestLaterTimestamp(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"2022-01-02T00:00:00Z", "2022-01-01T00:00:00Z", true},
+		{"2022-01-01T00:00:00Z", "2022-01-02T00:00:00Z", false},
+		{"2022-01-01T01:00:00+02:00", "2022-01-01T00:00:00Z", false},
+		{"2022-01-01T00:00:00.5Z", "2022-01-01T00:00:00Z", true},
+		{"2022-01-01T00:00:00Z", "", true},
+		{"", "", false},
+		{"b", "a", true},
+	}
+
+	for _, tt := range tests {
+		if got := LaterTimestamp(tt.a, tt.b); got != tt.want {
+			t.Errorf("LaterTimestamp(%q, %q) = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
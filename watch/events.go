@@ -0,0 +1,94 @@
+package watch
+
+import (
+	"time"
+
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+// Event is one of the event types below. Use a type switch to handle them.
+type Event interface {
+	// Name identifies the event type, e.g. "order_created".
+	Name() string
+	// ObservedAt is when the watcher saw the change, not when it happened.
+	ObservedAt() time.Time
+}
+
+type observed struct {
+	At time.Time `json:"observed_at"`
+}
+
+func (o observed) ObservedAt() time.Time { return o.At }
+
+// OrderCreated is emitted for active orders created since the watch window
+// started.
+type OrderCreated struct {
+	observed
+	Order api.Order `json:"order"`
+}
+
+// OrderFilled is emitted when an order is sold. Previous is the last state the
+// watcher saw, or nil if the order was created and filled between polls.
+type OrderFilled struct {
+	observed
+	Order    api.Order  `json:"order"`
+	Previous *api.Order `json:"previous,omitempty"`
+}
+
+type OrderCancelled struct {
+	observed
+	Order    api.Order  `json:"order"`
+	Previous *api.Order `json:"previous,omitempty"`
+}
+
+type OrderExpired struct {
+	observed
+	Order    api.Order  `json:"order"`
+	Previous *api.Order `json:"previous,omitempty"`
+}
+
+// OrderUpdated is emitted for any other change, e.g. an order becoming
+// inactive.
+type OrderUpdated struct {
+	observed
+	Order    api.Order  `json:"order"`
+	Previous *api.Order `json:"previous,omitempty"`
+}
+
+// AssetCreated is emitted for assets minted since the watch window started.
+type AssetCreated struct {
+	observed
+	Asset api.AssetWithOrders `json:"asset"`
+}
+
+// AssetTransferred is emitted when an asset's owner changes.
+type AssetTransferred struct {
+	observed
+	Asset api.AssetWithOrders `json:"asset"`
+	From  string              `json:"from"`
+	To    string              `json:"to"`
+}
+
+type AssetBurned struct {
+	observed
+	Asset api.AssetWithOrders `json:"asset"`
+}
+
+// AssetUpdated is emitted for any other change, e.g. a status or metadata
+// change. Previous is nil if the watcher hadn't seen the asset before, in
+// which case a transfer can't be told apart from other updates.
+type AssetUpdated struct {
+	observed
+	Asset    api.AssetWithOrders  `json:"asset"`
+	Previous *api.AssetWithOrders `json:"previous,omitempty"`
+}
+
+func (OrderCreated) Name() string     { return "order_created" }
+func (OrderFilled) Name() string      { return "order_filled" }
+func (OrderCancelled) Name() string   { return "order_cancelled" }
+func (OrderExpired) Name() string     { return "order_expired" }
+func (OrderUpdated) Name() string     { return "order_updated" }
+func (AssetCreated) Name() string     { return "asset_created" }
+func (AssetTransferred) Name() string { return "asset_transferred" }
+func (AssetBurned) Name() string      { return "asset_burned" }
+func (AssetUpdated) Name() string     { return "asset_updated" }
@@ -0,0 +1,310 @@
+// Package watch polls the orders and assets clients for recent changes and
+// emits them as typed events on a channel.
+//
+//	w := watch.New(watch.Config{
+//		Orders:       ordersClient,
+//		OrderFilters: []orders.ListOrdersConfig{{SellTokenAddress: "hero"}},
+//	})
+//	go w.Run(ctx)
+//	for e := range w.Events() {
+//		switch e := e.(type) {
+//		case watch.OrderFilled:
+//			...
+//		}
+//	}
+package watch
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultInterval = 30 * time.Second
+	DefaultOverlap  = 10 * time.Second
+	DefaultBuffer   = 100
+)
+
+type Config struct {
+	Orders orders.Client
+	Assets assets.Client
+	// Each filter is polled separately with its own window. The ordering,
+	// update timestamp and accumulator fields are set by the watcher.
+	OrderFilters []orders.ListOrdersConfig
+	AssetFilters []assets.ListAssetsConfig
+	Interval     time.Duration
+	// Overlap widens each window backwards to catch updates that were
+	// committed late or hidden by clock skew. Duplicates are dropped.
+	Overlap time.Duration
+	// Since is where the first window starts, defaulting to now so only new
+	// changes are reported.
+	Since time.Time
+	// Buffer is the capacity of the events channel.
+	Buffer int
+}
+
+// Watcher polls with UpdatedMinTimestamp windows and diffs the results against
+// the last state it saw. Sends on the events channel block, so a slow consumer
+// delays the next poll rather than losing events.
+type Watcher struct {
+	cfg    Config
+	events chan Event
+
+	orders map[int32]api.Order
+	assets map[string]api.AssetWithOrders
+
+	orderWindows []time.Time
+	assetWindows []time.Time
+}
+
+func New(cfg Config) *Watcher {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+
+	if cfg.Overlap <= 0 {
+		cfg.Overlap = DefaultOverlap
+	}
+
+	if cfg.Since.IsZero() {
+		cfg.Since = time.Now()
+	}
+
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = DefaultBuffer
+	}
+
+	w := &Watcher{
+		cfg:          cfg,
+		events:       make(chan Event, cfg.Buffer),
+		orders:       make(map[int32]api.Order),
+		assets:       make(map[string]api.AssetWithOrders),
+		orderWindows: make([]time.Time, len(cfg.OrderFilters)),
+		assetWindows: make([]time.Time, len(cfg.AssetFilters)),
+	}
+
+	for i := range w.orderWindows {
+		w.orderWindows[i] = cfg.Since
+	}
+
+	for i := range w.assetWindows {
+		w.assetWindows[i] = cfg.Since
+	}
+
+	return w
+}
+
+// Events returns the channel events are sent on. It's closed when Run returns.
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Run polls until ctx is done. Failed polls are logged and retried on the next
+// interval with the same window.
+func (w *Watcher) Run(ctx context.Context) error {
+	defer close(w.events)
+
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Poll(ctx); err != nil && ctx.Err() == nil {
+			log.Errorf("watch poll failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll runs every filter once, emitting the changes found. Run calls it on
+// each interval; it's exported for callers driving their own schedule.
+func (w *Watcher) Poll(ctx context.Context) error {
+	for i, filter := range w.cfg.OrderFilters {
+		if err := w.pollOrders(ctx, i, filter); err != nil {
+			return err
+		}
+	}
+
+	for i, filter := range w.cfg.AssetFilters {
+		if err := w.pollAssets(ctx, i, filter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *Watcher) pollOrders(ctx context.Context, i int, cfg orders.ListOrdersConfig) error {
+	start := time.Now()
+	from := w.orderWindows[i].Add(-w.cfg.Overlap)
+
+	cfg.Cursor, cfg.Orders = "", nil
+	cfg.Direction = "asc"
+	cfg.OrderBy = "updated_at"
+	cfg.UpdatedMinTimestamp = from.UTC().Format(time.RFC3339)
+
+	result, err := w.cfg.Orders.ListOrders(ctx, &cfg)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("watch found %d orders updated since %s", len(result), cfg.UpdatedMinTimestamp)
+	for _, order := range result {
+		if e := w.diffOrder(order, from, start); e != nil {
+			if err := w.emit(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.orderWindows[i] = start
+	w.pruneOrders(oldest(w.orderWindows).Add(-w.cfg.Overlap))
+	return nil
+}
+
+func (w *Watcher) diffOrder(order api.Order, from, now time.Time) Event {
+	at := observed{At: now}
+	prev, seen := w.orders[order.OrderId]
+	if seen && prev.Status == order.Status && prev.GetUpdatedTimestamp() == order.GetUpdatedTimestamp() {
+		return nil
+	}
+
+	w.orders[order.OrderId] = order
+
+	var previous *api.Order
+	if seen {
+		previous = &prev
+	}
+
+	switch {
+	case !seen && order.Status == "active" && since(order.GetTimestamp(), from):
+		return OrderCreated{observed: at, Order: order}
+	case order.Status == "filled":
+		return OrderFilled{observed: at, Order: order, Previous: previous}
+	case order.Status == "cancelled":
+		return OrderCancelled{observed: at, Order: order, Previous: previous}
+	case order.Status == "expired":
+		return OrderExpired{observed: at, Order: order, Previous: previous}
+	default:
+		return OrderUpdated{observed: at, Order: order, Previous: previous}
+	}
+}
+
+// pruneOrders forgets orders last updated before every filter's next window,
+// as they won't be returned again until they change. A change to a forgotten
+// active order is reported as an update without the previous order.
+func (w *Watcher) pruneOrders(before time.Time) {
+	for id, order := range w.orders {
+		updated, err := time.Parse(time.RFC3339Nano, order.GetUpdatedTimestamp())
+		if err == nil && updated.Before(before) {
+			delete(w.orders, id)
+		}
+	}
+}
+
+func (w *Watcher) pollAssets(ctx context.Context, i int, cfg assets.ListAssetsConfig) error {
+	start := time.Now()
+	from := w.assetWindows[i].Add(-w.cfg.Overlap)
+
+	cfg.Cursor, cfg.Assets = "", nil
+	cfg.Direction = "asc"
+	cfg.OrderBy = "updated_at"
+	cfg.UpdatedMinTimestamp = from.UTC().Format(time.RFC3339)
+
+	result, err := w.cfg.Assets.ListAssets(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("watch found %d assets updated since %s", len(result), cfg.UpdatedMinTimestamp)
+	for _, asset := range result {
+		if e := w.diffAsset(asset, from, start); e != nil {
+			if err := w.emit(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.assetWindows[i] = start
+	w.pruneAssets(oldest(w.assetWindows).Add(-w.cfg.Overlap))
+	return nil
+}
+
+// pruneAssets forgets assets last updated before every filter's next window,
+// as they won't be returned again until they change. A transfer of a
+// forgotten asset is reported as an update, since its previous owner isn't
+// known.
+func (w *Watcher) pruneAssets(before time.Time) {
+	for key, asset := range w.assets {
+		updated, err := time.Parse(time.RFC3339Nano, asset.GetUpdatedAt())
+		if err == nil && updated.Before(before) {
+			delete(w.assets, key)
+		}
+	}
+}
+
+func oldest(windows []time.Time) time.Time {
+	min := windows[0]
+	for _, t := range windows[1:] {
+		if t.Before(min) {
+			min = t
+		}
+	}
+
+	return min
+}
+
+func (w *Watcher) diffAsset(asset api.AssetWithOrders, from, now time.Time) Event {
+	at := observed{At: now}
+	key := strings.ToLower(asset.TokenAddress) + "/" + asset.TokenId
+	prev, seen := w.assets[key]
+	if seen && prev.User == asset.User && prev.Status == asset.Status && prev.GetUpdatedAt() == asset.GetUpdatedAt() {
+		return nil
+	}
+
+	// Assets are kept while they can be returned again, since a transfer can
+	// only be detected by knowing the previous owner.
+	w.assets[key] = asset
+
+	switch {
+	case asset.Status == "burned" && (!seen || prev.Status != "burned"):
+		return AssetBurned{observed: at, Asset: asset}
+	case seen && !strings.EqualFold(prev.User, asset.User):
+		return AssetTransferred{observed: at, Asset: asset, From: prev.User, To: asset.User}
+	case !seen && createdSince(asset, from):
+		return AssetCreated{observed: at, Asset: asset}
+	case seen:
+		return AssetUpdated{observed: at, Asset: asset, Previous: &prev}
+	default:
+		return AssetUpdated{observed: at, Asset: asset}
+	}
+}
+
+func createdSince(asset api.AssetWithOrders, from time.Time) bool {
+	created := asset.CreatedAt.Get()
+	return created != nil && since(*created, from)
+}
+
+func since(timestamp string, from time.Time) bool {
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	return err == nil && !t.Before(from)
+}
+
+func (w *Watcher) emit(ctx context.Context, e Event) error {
+	select {
+	case w.events <- e:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
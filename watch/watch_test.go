@@ -0,0 +1,264 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/deadloct/immutablex-go-lib/assets"
+	"github.com/deadloct/immutablex-go-lib/imxtest"
+	"github.com/deadloct/immutablex-go-lib/orders"
+	"github.com/immutable/imx-core-sdk-golang/imx/api"
+)
+
+func TestPollPrunesAssets(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	var recent, earlier api.AssetWithOrders
+	for _, a := range []struct {
+		asset   *api.AssetWithOrders
+		id      string
+		updated time.Time
+	}{
+		{&recent, "7", now},
+		{&earlier, "8", now.Add(-30 * time.Second)},
+	} {
+		content := fmt.Sprintf(`{"token_address": "0x6465ef3009f3c474774f4afb607a5d600ea71d95", "token_id": %q, "user": "0x1111111111111111111111111111111111111111", "status": "imx", "updated_at": %q}`,
+			a.id, a.updated.Format(time.RFC3339Nano))
+		if err := json.Unmarshal([]byte(content), a.asset); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := imxtest.NewAssetsClient(imxtest.DefaultFixtures())
+	client.AddAssets(recent, earlier)
+
+	w := New(Config{
+		Assets:       client,
+		AssetFilters: []assets.ListAssetsConfig{{Collection: "hero"}, {Collection: "portal"}},
+		Since:        now.Add(-time.Minute),
+	})
+
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The fixtures were last updated long before the first window.
+	if n := len(w.events); n != 2 {
+		t.Errorf("got %d events, want 2", n)
+	}
+
+	// The earlier asset is older than the next window with its overlap, so
+	// only the recent asset can be returned again and is kept.
+	if len(w.assets) != 1 {
+		t.Fatalf("kept %d assets, want 1", len(w.assets))
+	}
+
+	if _, ok := w.assets["0x6465ef3009f3c474774f4afb607a5d600ea71d95/7"]; !ok {
+		t.Error("recent asset was pruned")
+	}
+}
+
+func order(t *testing.T, id int32, status string, created, updated time.Time) api.Order {
+	content := fmt.Sprintf(`{"order_id": %d, "status": %q, "user": "0x1111111111111111111111111111111111111111", "timestamp": %q, "updated_timestamp": %q}`,
+		id, status, created.Format(time.RFC3339Nano), updated.Format(time.RFC3339Nano))
+
+	var o api.Order
+	if err := json.Unmarshal([]byte(content), &o); err != nil {
+		t.Fatal(err)
+	}
+
+	return o
+}
+
+func asset(t *testing.T, id, user string, created, updated time.Time) api.AssetWithOrders {
+	content := fmt.Sprintf(`{"token_address": "0x6465ef3009f3c474774f4afb607a5d600ea71d95", "token_id": %q, "user": %q, "status": "imx", "created_at": %q, "updated_at": %q}`,
+		id, user, created.Format(time.RFC3339Nano), updated.Format(time.RFC3339Nano))
+
+	var a api.AssetWithOrders
+	if err := json.Unmarshal([]byte(content), &a); err != nil {
+		t.Fatal(err)
+	}
+
+	return a
+}
+
+// names polls once per snapshot, each replacing what the clients return, and
+// collects the names of the events emitted.
+func names(t *testing.T, w *Watcher, snapshots []func(*imxtest.OrdersClient, *imxtest.AssetsClient)) []string {
+	var got []string
+	for _, snapshot := range snapshots {
+		o, a := imxtest.NewOrdersClient(nil), imxtest.NewAssetsClient(nil)
+		snapshot(o, a)
+		w.cfg.Orders, w.cfg.Assets = o, a
+
+		if err := w.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		for len(w.events) > 0 {
+			got = append(got, (<-w.events).Name())
+		}
+	}
+
+	return got
+}
+
+func TestOrderEvents(t *testing.T) {
+	now := time.Now().UTC()
+	old := now.Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		snapshots []func(*imxtest.OrdersClient, *imxtest.AssetsClient)
+		want      []string
+	}{
+		{
+			name: "created then filled",
+			snapshots: []func(*imxtest.OrdersClient, *imxtest.AssetsClient){
+				func(o *imxtest.OrdersClient, _ *imxtest.AssetsClient) { o.AddOrders(order(t, 1, "active", now, now)) },
+				func(o *imxtest.OrdersClient, _ *imxtest.AssetsClient) {
+					o.AddOrders(order(t, 1, "filled", now, now.Add(time.Second)))
+				},
+			},
+			want: []string{"order_created", "order_filled"},
+		},
+		{
+			name: "unchanged",
+			snapshots: []func(*imxtest.OrdersClient, *imxtest.AssetsClient){
+				func(o *imxtest.OrdersClient, _ *imxtest.AssetsClient) { o.AddOrders(order(t, 1, "active", now, now)) },
+				func(o *imxtest.OrdersClient, _ *imxtest.AssetsClient) { o.AddOrders(order(t, 1, "active", now, now)) },
+			},
+			want: []string{"order_created"},
+		},
+		{
+			name: "filled between polls",
+			snapshots: []func(*imxtest.OrdersClient, *imxtest.AssetsClient){
+				func(o *imxtest.OrdersClient, _ *imxtest.AssetsClient) {},
+				func(o *imxtest.OrdersClient, _ *imxtest.AssetsClient) { o.AddOrders(order(t, 1, "filled", now, now)) },
+			},
+			want: []string{"order_filled"},
+		},
+		{
+			name: "created before the window",
+			snapshots: []func(*imxtest.OrdersClient, *imxtest.AssetsClient){
+				func(o *imxtest.OrdersClient, _ *imxtest.AssetsClient) { o.AddOrders(order(t, 1, "active", old, now)) },
+			},
+			want: []string{"order_updated"},
+		},
+		{
+			name: "updated before the window",
+			snapshots: []func(*imxtest.OrdersClient, *imxtest.AssetsClient){
+				func(o *imxtest.OrdersClient, _ *imxtest.AssetsClient) { o.AddOrders(order(t, 1, "active", old, old)) },
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := New(Config{
+				OrderFilters: []orders.ListOrdersConfig{{}},
+				Since:        now.Add(-time.Minute),
+			})
+
+			if got := names(t, w, tt.snapshots); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got events %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssetEvents(t *testing.T) {
+	now := time.Now().UTC()
+	old := now.Add(-time.Hour)
+	alice := "0x1111111111111111111111111111111111111111"
+	bob := "0x2222222222222222222222222222222222222222"
+
+	tests := []struct {
+		name      string
+		snapshots []func(*imxtest.OrdersClient, *imxtest.AssetsClient)
+		want      []string
+	}{
+		{
+			name: "minted then transferred",
+			snapshots: []func(*imxtest.OrdersClient, *imxtest.AssetsClient){
+				func(_ *imxtest.OrdersClient, a *imxtest.AssetsClient) { a.AddAssets(asset(t, "1", alice, now, now)) },
+				func(_ *imxtest.OrdersClient, a *imxtest.AssetsClient) {
+					a.AddAssets(asset(t, "1", bob, now, now.Add(time.Second)))
+				},
+			},
+			want: []string{"asset_created", "asset_transferred"},
+		},
+		{
+			name: "transferred twice",
+			snapshots: []func(*imxtest.OrdersClient, *imxtest.AssetsClient){
+				func(_ *imxtest.OrdersClient, a *imxtest.AssetsClient) { a.AddAssets(asset(t, "1", alice, old, now)) },
+				func(_ *imxtest.OrdersClient, a *imxtest.AssetsClient) {
+					a.AddAssets(asset(t, "1", bob, old, now.Add(time.Second)))
+				},
+				func(_ *imxtest.OrdersClient, a *imxtest.AssetsClient) {
+					a.AddAssets(asset(t, "1", alice, old, now.Add(2*time.Second)))
+				},
+			},
+			want: []string{"asset_updated", "asset_transferred", "asset_transferred"},
+		},
+		{
+			name: "unchanged",
+			snapshots: []func(*imxtest.OrdersClient, *imxtest.AssetsClient){
+				func(_ *imxtest.OrdersClient, a *imxtest.AssetsClient) { a.AddAssets(asset(t, "1", alice, now, now)) },
+				func(_ *imxtest.OrdersClient, a *imxtest.AssetsClient) { a.AddAssets(asset(t, "1", alice, now, now)) },
+			},
+			want: []string{"asset_created"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := New(Config{
+				AssetFilters: []assets.ListAssetsConfig{{Collection: "hero"}},
+				Since:        now.Add(-time.Minute),
+			})
+
+			if got := names(t, w, tt.snapshots); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got events %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPollPrunesOrders(t *testing.T) {
+	now := time.Now().UTC()
+	client := imxtest.NewOrdersClient(nil)
+	client.AddOrders(
+		order(t, 1, "active", now, now),
+		order(t, 2, "active", now, now.Add(-30*time.Second)),
+		order(t, 3, "filled", now, now.Add(-30*time.Second)),
+	)
+
+	w := New(Config{
+		Orders:       client,
+		OrderFilters: []orders.ListOrdersConfig{{}, {User: "0x1111111111111111111111111111111111111111"}},
+		Since:        now.Add(-time.Minute),
+	})
+
+	if err := w.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Orders older than the next window with its overlap are forgotten,
+	// whatever their status.
+	if _, ok := w.orders[1]; len(w.orders) != 1 || !ok {
+		t.Errorf("kept orders %v, want only 1", w.orders)
+	}
+}
+
+func TestOldest(t *testing.T) {
+	now := time.Now()
+	windows := []time.Time{now, now.Add(-time.Hour), now.Add(time.Hour)}
+	if got := oldest(windows); !got.Equal(windows[1]) {
+		t.Errorf("got %v, want %v", got, windows[1])
+	}
+}